@@ -0,0 +1,114 @@
+package duckduckgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInstantAnswer(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *InstantAnswer
+	}{
+		{
+			name: "article with infobox",
+			body: `{"Type":"A","Heading":"Go (programming language)","Entity":"programming language",
+				"AbstractText":"Go is a language.","AbstractSource":"Wikipedia","AbstractURL":"https://en.wikipedia.org/wiki/Go",
+				"Image":"/i/go.png","ImageWidth":"280","ImageHeight":300,"ImageIsLogo":"1","Answer":"","Redirect":"",
+				"Infobox":{"content":[
+					{"label":"Designed by","value":"Robert Griesemer","data_type":"string","wiki_order":"0"},
+					{"label":"Wikidata id","value":{"entity-type":"item","id":"Q37227"},"data_type":"wikidata","wiki_order":1}
+				],"meta":[{"label":"article_title","value":"Go","data_type":"string"}]},
+				"Results":[{"Text":"Official site","FirstURL":"https://go.dev/","Result":"<a href=\"https://go.dev/\">Official site</a>","Icon":{"URL":"/i/go.dev.ico"}}],
+				"RelatedTopics":[]}`,
+			want: &InstantAnswer{
+				Type:           InstantAnswerArticle,
+				Heading:        "Go (programming language)",
+				Entity:         "programming language",
+				AbstractText:   "Go is a language.",
+				AbstractSource: "Wikipedia",
+				AbstractURL:    "https://en.wikipedia.org/wiki/Go",
+				Image:          "https://duckduckgo.com/i/go.png",
+				ImageWidth:     280,
+				ImageHeight:    300,
+				ImageIsLogo:    true,
+				Infobox: &Infobox{
+					Content: []InfoboxItem{
+						{Label: "Designed by", Value: "Robert Griesemer", DataType: "string"},
+						{Label: "Wikidata id", Value: `{"entity-type":"item","id":"Q37227"}`, DataType: "wikidata", WikiOrder: 1},
+					},
+					Meta: []InfoboxItem{{Label: "article_title", Value: "Go", DataType: "string"}},
+				},
+				Results: []AnswerTopic{{
+					Text:   "Official site",
+					URL:    "https://go.dev/",
+					Icon:   "https://duckduckgo.com/i/go.dev.ico",
+					Result: `<a href="https://go.dev/">Official site</a>`,
+				}},
+			},
+		},
+		{
+			name: "disambiguation",
+			body: `{"Type":"D","Heading":"Go","Image":"","ImageWidth":"","ImageHeight":"","ImageIsLogo":"","Infobox":"",
+				"Results":[],
+				"RelatedTopics":[
+					{"Text":"Go, a board game","FirstURL":"https://duckduckgo.com/Go_(game)","Icon":{"URL":"https://example.com/go.png"}},
+					{"Name":"Computing","Topics":[
+						{"Text":"Go, a programming language","FirstURL":"https://duckduckgo.com/Go_(programming_language)","Icon":{"URL":""}}
+					]}
+				]}`,
+			want: &InstantAnswer{
+				Type:    InstantAnswerDisambiguation,
+				Heading: "Go",
+				Results: []AnswerTopic{},
+				RelatedTopics: []AnswerTopic{
+					{Text: "Go, a board game", URL: "https://duckduckgo.com/Go_(game)", Icon: "https://example.com/go.png"},
+				},
+				Groups: []AnswerGroup{{
+					Name:   "Computing",
+					Topics: []AnswerTopic{{Text: "Go, a programming language", URL: "https://duckduckgo.com/Go_(programming_language)"}},
+				}},
+			},
+		},
+		{
+			name: "structured answer",
+			body: `{"Type":"E","Answer":{"from":"calculator","result":"4"},"AnswerType":"calc","Infobox":"","Results":[],"RelatedTopics":[]}`,
+			want: &InstantAnswer{
+				Type:       InstantAnswerExclusive,
+				Answer:     `{"from":"calculator","result":"4"}`,
+				AnswerType: "calc",
+				Results:    []AnswerTopic{},
+			},
+		},
+		{
+			name: "numeric answer",
+			body: `{"Type":"E","Answer":4,"AnswerType":"calc","Results":[],"RelatedTopics":[]}`,
+			want: &InstantAnswer{
+				Type:       InstantAnswerExclusive,
+				Answer:     "4",
+				AnswerType: "calc",
+				Results:    []AnswerTopic{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseInstantAnswer([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInstantAnswer() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInstantAnswerInvalid(t *testing.T) {
+	for _, body := range []string{`<html>`, `{"Infobox":{"content":"none"}}`} {
+		if _, err := parseInstantAnswer([]byte(body)); err == nil {
+			t.Errorf("parseInstantAnswer(%s) returned no error", body)
+		}
+	}
+}
@@ -2,12 +2,15 @@ package duckduckgo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/antchfx/htmlquery"
@@ -17,65 +20,223 @@ import (
 
 /**
 * DuckDuckgo_search async class to get search results from duckduckgo.com.
+* Build it with New; it is configured once, by the options passed to New.
 **/
 type AsyncDDGS struct {
-	Executor *http.Client
-	Proxies  map[string]string
-	Timeout  int
+	httpClient  *http.Client
+	proxies     map[string]string
+	timeout     time.Duration
+	headers     http.Header
+	baseURLs    map[string]string
+	logger      Logger
+	proxyPool   *ProxyPool
+	retryPolicy RetryPolicy
+	limiter     *limiter
+	strict      bool
+
+	executorOnce sync.Once
+	executor     *http.Client
+	executorErr  error
 }
 
+/**
+* Deprecated: use New.
+**/
 func NewAsyncDDGS(headers map[string]string, proxies map[string]string, timeout int) *AsyncDDGS {
-	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
+	return newAsyncDDGS(WithHeaders(headers), WithProxies(proxies), WithTimeout(time.Duration(timeout)*time.Second))
+}
+
+func (a *AsyncDDGS) getExecutor() (*http.Client, error) {
+	a.executorOnce.Do(func() {
+		a.executor, a.executorErr = a.buildExecutor()
+	})
+	return a.executor, a.executorErr
+}
+
+/**
+* Build the URL of path on host, honouring WithBaseURLs.
+**/
+func (a *AsyncDDGS) url(host string, path string) string {
+	if baseURL, ok := a.baseURLs[host]; ok {
+		return strings.TrimSuffix(baseURL, "/") + path
 	}
-	return &AsyncDDGS{
-		Executor: client,
-		Proxies:  proxies,
-		Timeout:  timeout,
+	return fmt.Sprintf("https://%s%s", host, path)
+}
+
+func (a *AsyncDDGS) logf(format string, v ...any) {
+	if a.logger != nil {
+		a.logger.Printf(format, v...)
 	}
 }
 
-func (a *AsyncDDGS) getExecutor() *http.Client {
-	if a.Executor == nil {
-		a.Executor = &http.Client{}
+/**
+* ProxyStats reports the health of every proxy of the pool set with
+* WithProxyPool, or nil without a pool.
+**/
+func (a *AsyncDDGS) ProxyStats() []ProxyStats {
+	if a.proxyPool == nil {
+		return nil
 	}
-	return a.Executor
+	return a.proxyPool.Stats()
 }
 
-func (a AsyncDDGS) host(host string) string {
-	if a.Proxies != nil {
-		if proxy, ok := a.Proxies[host]; ok {
-			return proxy
+/**
+* Fetch url, retrying according to the retry policy of ctx or of the client.
+* Rate limiting and HTTP errors are reported as *RatelimitError and *StatusError.
+**/
+func (a *AsyncDDGS) agetURL(ctx context.Context, method string, url string, data []byte, params map[string]string) ([]byte, error) {
+	policy := a.retryPolicyFor(ctx)
+	for attempt := 1; ; attempt++ {
+		respContent, err := a.agetURLOnce(ctx, method, url, data, params)
+		if err == nil {
+			return respContent, nil
+		}
+		var retryAfter time.Duration
+		var ratelimitErr *RatelimitError
+		var statusErr *StatusError
+		switch {
+		case ctx.Err() != nil:
+			return nil, err
+		case errors.As(err, &ratelimitErr):
+			if !policy.retryableRatelimit(ratelimitErr.StatusCode) {
+				return nil, err
+			}
+			retryAfter = ratelimitErr.RetryAfter
+		case errors.As(err, &statusErr):
+			if !policy.retryableStatus(statusErr.StatusCode) {
+				return nil, err
+			}
+		case !policy.RetryOnNetworkError:
+			return nil, err
+		}
+		if attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+		delay := policy.delay(attempt, retryAfter)
+		a.logf("duckduckgo: retrying %s %s in %s after attempt %d: %v", method, url, delay, attempt, err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
 		}
 	}
-	return host
-
 }
 
-func (a *AsyncDDGS) agetURL(method string, url string, data []byte, params map[string]string) ([]byte, error) {
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+func (a *AsyncDDGS) agetURLOnce(ctx context.Context, method string, url string, data []byte, params map[string]string) ([]byte, error) {
+	resp, finish, err := a.send(ctx, method, url, data, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	respContent, err := io.ReadAll(resp.Body)
+	if err == nil {
+		err = checkResponse(resp, respContent)
+	}
+	finish(err)
 	if err != nil {
 		return nil, err
 	}
+	return respContent, nil
+}
+
+/**
+* Send one request through the proxy, limiter and client of a, with header
+* added to the default headers. Unless an error is returned, the caller owns
+* the response and must call finish with the outcome once it has read the
+* body: finish closes it, frees the in-flight slot and scores the proxy.
+**/
+func (a *AsyncDDGS) send(ctx context.Context, method string, url string, data []byte, params map[string]string, header http.Header) (resp *http.Response, finish func(err error), err error) {
+	executor, err := a.getExecutor()
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.sendWith(ctx, executor, method, url, data, params, header)
+}
+
+/**
+* Like send, but for responses streamed for as long as ctx allows: the timeout
+* of the client only bounds the wait for the response headers, not the
+* reading of the body.
+**/
+func (a *AsyncDDGS) sendStream(ctx context.Context, method string, url string, data []byte, params map[string]string, header http.Header) (resp *http.Response, finish func(err error), err error) {
+	executor, err := a.getExecutor()
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout := executor.Timeout
+	if timeout <= 0 {
+		return a.sendWith(ctx, executor, method, url, data, params, header)
+	}
+	client := *executor
+	client.Timeout = 0
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(timeout, func() {
+		cancel(fmt.Errorf("%s %s: no response within %s", method, url, timeout))
+	})
+	resp, sent, err := a.sendWith(ctx, &client, method, url, data, params, header)
+	timer.Stop()
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+			err = cause
+		}
+		cancel(nil)
+		return nil, nil, err
+	}
+	return resp, func(err error) {
+		sent(err)
+		cancel(nil)
+	}, nil
+}
+
+func (a *AsyncDDGS) sendWith(ctx context.Context, executor *http.Client, method string, url string, data []byte, params map[string]string, header http.Header) (resp *http.Response, finish func(err error), err error) {
+	var proxy *pooledProxy
+	if a.proxyPool != nil {
+		ctx, proxy = a.proxyPool.attach(ctx)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range a.headers {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	q := req.URL.Query()
 	for key, value := range params {
 		q.Add(key, value)
 	}
 	req.URL.RawQuery = q.Encode()
-	resp, err := a.getExecutor().Do(req)
+	reportSentURL(ctx, req.URL.String())
+	release, err := a.limiter.acquire(ctx, req.URL.Host)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
-	respContent, err := io.ReadAll(resp.Body)
+	resp, err = executor.Do(req)
 	if err != nil {
-		return nil, err
-	}
-	return respContent, nil
+		release()
+		a.logf("duckduckgo: %s %s: %v", method, url, err)
+		if proxy != nil && ctx.Err() == nil {
+			a.proxyPool.markFailure(proxy)
+		}
+		return nil, nil, err
+	}
+	a.logf("duckduckgo: %s %s: %s", method, url, resp.Status)
+	reportSentURL(ctx, resp.Request.URL.String())
+	return resp, func(err error) {
+		resp.Body.Close()
+		release()
+		if proxy != nil {
+			if isRatelimit(err) {
+				a.proxyPool.markFailure(proxy)
+			} else {
+				a.proxyPool.markSuccess(proxy)
+			}
+		}
+	}, nil
 }
 
-func (a *AsyncDDGS) agetVqd(keywords string) (string, error) {
-	respContent, err := a.agetURL("POST", fmt.Sprintf("https://%s", a.host("duckduckgo.com")), nil, map[string]string{"q": keywords})
+func (a *AsyncDDGS) agetVqd(ctx context.Context, keywords string) (string, error) {
+	respContent, err := a.agetURL(ctx, "POST", a.url("duckduckgo.com", "/"), nil, map[string]string{"q": keywords})
 	if err != nil {
 		return "", err
 	}
@@ -83,370 +244,570 @@ func (a *AsyncDDGS) agetVqd(keywords string) (string, error) {
 }
 
 /**
-*	region: wt-wt, us-en, uk-en, ru-ru, etc. Defaults to "wt-wt".
-*   timelimit: d, w, m, y. Defaults to None.
-*	safesearch: "moderate", "off", "on". Defaults to "moderate".
+* Text searches the web. See TextRequest for the available parameters.
+* If some pages fail, the results of the others are returned together with
+* a *PartialResultsError, unless the client is strict.
 **/
-func (a *AsyncDDGS) Text(keywords string, region string, safesearch string, timelimit string, backend string, maxResults int) ([]map[string]string, error) {
-	if region == "" {
-		region = "wt-wt"
+func (a *AsyncDDGS) Text(req TextRequest) ([]TextResult, error) {
+	return a.TextContext(context.Background(), req)
+}
+
+// TextContext is like Text but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) TextContext(ctx context.Context, req TextRequest) ([]TextResult, error) {
+	resp, err := a.SearchText(ctx, req)
+	if resp == nil {
+		return nil, err
 	}
-	if safesearch == "" {
-		safesearch = "moderate"
+	return resp.Results, err
+}
+
+/**
+* SearchText is like TextContext but also reports how the results were
+* obtained. With BackendAuto, the next backend is tried whenever one is rate
+* limited, fails or finds nothing; if all of them fail, the best response
+* is returned together with the error of every backend. BackendRace works
+* the same way but runs the backends concurrently, see TextRequest.HedgeDelay.
+* The pages described by the response are those of the backend it came from.
+**/
+func (a *AsyncDDGS) SearchText(ctx context.Context, req TextRequest) (*TextResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	if backend == "" {
-		backend = "api"
+	req = req.withDefaults()
+
+	start := time.Now()
+	resp, err := a.searchText(ctx, req)
+	if resp != nil {
+		resp.Elapsed = time.Since(start)
 	}
+	return resp, err
+}
 
-	if backend == "api" {
-		results, err := a.textAPI(keywords, region, safesearch, timelimit, maxResults)
-		if err != nil {
-			return nil, err
-		}
-		return results, nil
-	} else if backend == "html" {
-		results, err := a.textHTML(keywords, region, safesearch, timelimit, maxResults)
-		if err != nil {
-			return nil, err
-		}
-		return results, nil
-	} else if backend == "lite" {
-		results, err := a.textLite(keywords, region, timelimit, maxResults)
-		if err != nil {
-			return nil, err
+func (a *AsyncDDGS) searchText(ctx context.Context, req TextRequest) (*TextResponse, error) {
+	if req.Backend == BackendRace {
+		return a.raceText(ctx, req)
+	}
+	failures := &textFailures{}
+	for _, backend := range req.backendOrder() {
+		resp, err := a.textSearchResponse(ctx, req, backend)
+		if ctx.Err() != nil || !shouldFallback(resp.Results, err) {
+			return resp, err
 		}
-		return results, nil
+		failures.add(a, resp, err)
 	}
-	return nil, fmt.Errorf("Invalid backend")
+	return failures.result()
 }
 
-func (a *AsyncDDGS) textAPI(keywords string, region string, safesearch string, timelimit string, maxResults int) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("Keywords is mandatory")
+/**
+* Whether BackendAuto should move on from a backend that returned results and err.
+**/
+func shouldFallback(results []TextResult, err error) bool {
+	return len(results) == 0 || isRatelimit(err)
+}
+
+/**
+* The backends a text search gave up on. The response with the most results
+* is kept in case no backend succeeds.
+**/
+type textFailures struct {
+	best    *TextResponse
+	bestErr error
+	errs    []error
+}
+
+func (f *textFailures) add(a *AsyncDDGS, resp *TextResponse, err error) {
+	if f.best == nil || len(resp.Results) > len(f.best.Results) {
+		f.best, f.bestErr = resp, err
 	}
-	if region == "" {
-		region = "wt-wt"
+	if err == nil {
+		err = fmt.Errorf("no results")
 	}
-	if safesearch == "" {
-		safesearch = "moderate"
+	a.logf("duckduckgo: text backend %s: %v", resp.Backend, err)
+	f.errs = append(f.errs, fmt.Errorf("backend %s: %w", resp.Backend, err))
+}
+
+func (f *textFailures) result() (*TextResponse, error) {
+	if len(f.best.Results) > 0 || len(f.errs) == 1 {
+		return f.best, f.bestErr
 	}
+	return f.best, errors.Join(f.errs...)
+}
 
-	vqd, err := a.agetVqd(keywords)
-	if err != nil {
-		return nil, err
+/**
+* Search with a single backend. With req.KeepSpelling, a search DuckDuckGo
+* answered for a corrected query is repeated once with the parameters of its
+* "Search only for" link. The spelling reported is then that of the second
+* search, which is only unapplied if DuckDuckGo kept the query as typed.
+* The api backend has no such link, so its search is repeated with html.
+**/
+func (a *AsyncDDGS) textSearchResponse(ctx context.Context, req TextRequest, backend Backend) (*TextResponse, error) {
+	req.Backend = backend
+	resp, err := a.textSearch(ctx, req, nil)
+	spelling := resp.Spelling
+	if err != nil || !req.KeepSpelling || spelling == nil || !spelling.Applied {
+		return resp, err
 	}
-	payload := map[string]string{
-		"q":           keywords,
-		"kl":          region,
-		"l":           region,
-		"vqd":         vqd,
-		"bing_market": region,
-		"a":           "ftsa",
+	if backend == BackendAPI {
+		return a.textSearchResponse(ctx, req, BackendHTML)
 	}
-	safesearch = strings.ToLower(safesearch)
-	if safesearch == "moderate" {
-		payload["ex"] = "-1"
-	} else if safesearch == "off" {
-		payload["ex"] = "-2"
-	} else if safesearch == "on" { // strict
-		payload["p"] = "1"
+	if !spelling.searchesOriginal(req.Keywords) {
+		return resp, err
 	}
-	if timelimit != "" {
-		payload["df"] = timelimit
+	req.Keywords = spelling.original["q"]
+	resp, err = a.textSearch(ctx, req, spelling.original)
+	if resp.Spelling == nil {
+		resp.Spelling = &Spelling{CorrectedQuery: spelling.CorrectedQuery}
 	}
+	return resp, err
+}
 
-	cache := make(map[string]bool)
-	results := make([]map[string]string, 1100)
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	textAPIPage := func(s int, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("GET", "https://links.duckduckgo.com/d.js", nil, payload)
-		if err != nil {
-			return
-		}
-		pageData, err := textExtractJSON(respContent, keywords)
-		if err != nil {
-			return
+/**
+* Fetch the pages of req.Backend, with params overriding the query parameters
+* of every page. The spelling correction and related searches are those of
+* the first page.
+**/
+func (a *AsyncDDGS) textSearch(ctx context.Context, req TextRequest, params map[string]string) (*TextResponse, error) {
+	resp := &TextResponse{ResponseMeta: ResponseMeta{Backend: req.Backend, Region: req.Region}}
+	pager, err := a.textPagerFor(req.Backend)(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	for key, value := range params {
+		pager.params[key] = value
+	}
+	b := textBackends[req.Backend]
+	var first atomic.Pointer[TextResponse]
+	pager.inspect = func(index int, body []byte) {
+		if index == 0 {
+			first.Store(&TextResponse{Spelling: b.spelling(body), RelatedSearches: b.related(body)})
 		}
+	}
+	results, meta, err := pager.searchResponse(ctx, a)
+	meta.Backend, meta.Region = req.Backend, req.Region
+	resp.Results, resp.ResponseMeta = limitResults(results, req.MaxResults), meta
+	if first := first.Load(); first != nil {
+		resp.Spelling, resp.RelatedSearches = first.Spelling, first.RelatedSearches
+	}
+	return resp, err
+}
 
-		for _, row := range pageData {
-			href, exist := row["u"].(string)
-			if !exist {
-				continue
-			}
-			if href != "" && !cache[href] && href != fmt.Sprintf("http://www.google.com/search?q=%s", keywords) {
-				cache[href] = true
-				body := normalize(row["a"].(string))
-				if body != "" {
-					priority++
-					result := map[string]string{
-						"title": normalize(row["t"].(string)),
-						"href":  normalizeURL(href),
-						"body":  body,
-					}
-					mu.Lock()
-					results[priority] = result
-					mu.Unlock()
+func (a *AsyncDDGS) textPagerFor(backend Backend) func(ctx context.Context, req TextRequest) (paginator[TextResult], error) {
+	switch backend {
+	case BackendHTML:
+		return a.textHTMLPager
+	case BackendLite:
+		return a.textLitePager
+	default:
+		return a.textAPIPager
+	}
+}
+
+/**
+* TextIter is like TextContext but yields results in rank order as pages
+* arrive, and stops fetching pages once the iterator is closed or
+* req.MaxResults results have been read. With BackendAuto or BackendRace,
+* the backends are tried one after the other, and the next backend is only
+* tried while no result has been yielded yet.
+**/
+func (a *AsyncDDGS) TextIter(ctx context.Context, req TextRequest) *Iterator[TextResult] {
+	if err := req.Validate(); err != nil {
+		return failedIterator[TextResult](ctx, err)
+	}
+	req = req.withDefaults()
+	backends := req.backendOrder()
+	if len(backends) == 1 {
+		return iteratePages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[TextResult], error) {
+			return a.textPagerFor(backends[0])(ctx, req)
+		})
+	}
+	return newIterator(ctx, func(ctx context.Context, emit func(result TextResult) bool) error {
+		var errs []error
+		for _, backend := range backends {
+			count := 0
+			it := iteratePages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[TextResult], error) {
+				return a.textPagerFor(backend)(ctx, req)
+			})
+			for it.Next() {
+				count++
+				if !emit(it.Result()) {
+					it.Close()
+					return ctx.Err()
 				}
 			}
+			err := it.Err()
+			it.Close()
+			if count > 0 || ctx.Err() != nil {
+				return err
+			}
+			if err == nil {
+				err = fmt.Errorf("no results")
+			}
+			a.logf("duckduckgo: text backend %s: %v", backend, err)
+			errs = append(errs, fmt.Errorf("backend %s: %w", backend, err))
 		}
+		return errors.Join(errs...)
+	})
+}
+
+func (a *AsyncDDGS) textAPIPager(ctx context.Context, req TextRequest) (paginator[TextResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
+	vqd, err := a.agetVqd(ctx, keywords)
+	if err != nil {
+		return paginator[TextResult]{}, err
 	}
-	wg.Add(1)
-	go textAPIPage(0, 0)
-	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 500})
-		for i, s := range rangeFunc(23, maxResults, 50) {
-			wg.Add(1)
-			go textAPIPage(s, i+1)
-		}
+	payload := map[string]string{
+		"q":           keywords,
+		"kl":          string(req.Region),
+		"l":           string(req.Region),
+		"vqd":         vqd,
+		"bing_market": string(req.Region),
+		"a":           "ftsa",
+	}
+	switch req.SafeSearch {
+	case SafeSearchOn: // strict
+		payload["p"] = "1"
+	case SafeSearchOff:
+		payload["ex"] = "-2"
+	default:
+		payload["ex"] = "-1"
+	}
+	if req.TimeLimit != "" {
+		payload["df"] = string(req.TimeLimit)
 	}
-	wg.Wait()
 
-	return lo.Slice(lo.Filter(results, func(res map[string]string, _ int) bool {
-		return res != nil
-	}), 0, maxResults), nil
+	pager := a.textPager(BackendAPI, keywords, payload)
+	if maxResults > 0 {
+		maxResults = lo.Min([]int{maxResults, MaxTextResults})
+		pager.offsets = append(pager.offsets, rangeFunc(23, maxResults, 50)...)
+	}
+	return pager, nil
 }
 
-func (a *AsyncDDGS) textHTML(keywords string, region string, safesearch string, timelimit string, maxResults int) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("Keywords is mandatory")
-	}
-	a.Executor.Timeout = time.Duration(a.Timeout) * time.Second
-	safesearchBase := map[string]string{
-		"on":       "1",
-		"moderate": "-1",
-		"off":      "-2",
-	}
+func (a *AsyncDDGS) textHTMLPager(ctx context.Context, req TextRequest) (paginator[TextResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
 	payload := map[string]string{
 		"q":   keywords,
-		"kl":  region,
-		"p":   safesearchBase[strings.ToLower(safesearch)],
+		"kl":  string(req.Region),
+		"p":   req.SafeSearch.value(),
 		"o":   "json",
 		"api": "d.js",
 	}
-	if timelimit != "" {
-		payload["df"] = timelimit
+	if req.TimeLimit != "" {
+		payload["df"] = string(req.TimeLimit)
 	}
 	if maxResults > 20 {
-		vqd, err := a.agetVqd(keywords)
+		vqd, err := a.agetVqd(ctx, keywords)
 		if err != nil {
-			return nil, err
+			return paginator[TextResult]{}, err
 		}
 		payload["vqd"] = vqd
 	}
-	cache := make(map[string]bool)
-	results := make([]map[string]string, 1100)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	textHTMLPage := func(s int, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("POST", "https://html.duckduckgo.com/html", nil, payload)
-		if err != nil {
-			return
-		}
-		if bytes.Contains(respContent, []byte("No  results.")) {
-			return
-		}
-		tree, err := htmlquery.Parse(bytes.NewReader(respContent))
-		if err != nil {
-			return
-		}
-		for _, e := range htmlquery.Find(tree, "//div[h2]") {
-			href := htmlquery.FindOne(e, "./a/@href")
-			if href != nil {
-				href := htmlquery.InnerText(href)
-				if href != "" && !cache[href] && !strings.HasPrefix(href, "http://www.google.com/search?q=") && !strings.HasPrefix(href, "https://duckduckgo.com/y.js?ad_domain") {
-					cache[href] = true
-					title := htmlquery.FindOne(e, "./h2/a/text()")
-					body := htmlquery.Find(e, "./a//text()")
-					priority++
-					result := map[string]string{
-						"title": normalize(htmlquery.InnerText(title)),
-						"href":  normalizeURL(href),
-						"body":  normalize(strings.Join(lo.Map(body, func(d *html.Node, _ int) string { return d.Data }), "")),
-					}
-					mu.Lock()
-					results[priority] = result
-					mu.Unlock()
-				}
-			}
-		}
-	}
-	wg.Add(1)
-	go textHTMLPage(0, 0)
+	pager := a.textPager(BackendHTML, keywords, payload)
 	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 500})
-		for i, s := range rangeFunc(23, maxResults, 50) {
-			wg.Add(1)
-			go textHTMLPage(s, i+1)
-		}
+		maxResults = lo.Min([]int{maxResults, MaxTextResults})
+		pager.offsets = append(pager.offsets, rangeFunc(23, maxResults, 50)...)
 	}
-	wg.Wait()
-	return lo.Slice(lo.Filter(results, func(res map[string]string, _ int) bool {
-		return res != nil
-	}), maxResults, 0), nil
+	return pager, nil
 }
 
-func (a *AsyncDDGS) textLite(keywords string, region string, timelimit string, maxResults int) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("Keywords is mandatory")
-	}
-	a.Executor.Timeout = time.Duration(a.Timeout) * time.Second
+func (a *AsyncDDGS) textLitePager(ctx context.Context, req TextRequest) (paginator[TextResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
 	payload := map[string]string{
 		"q":   keywords,
 		"o":   "json",
 		"api": "d.js",
-		"kl":  region,
+		"kl":  string(req.Region),
 	}
-	if timelimit != "" {
-		payload["df"] = timelimit
+	if req.TimeLimit != "" {
+		payload["df"] = string(req.TimeLimit)
 	}
-	cache := make(map[string]bool)
-	results := make([]map[string]string, 1100)
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	textLitePage := func(s int, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("POST", "https://lite.duckduckgo.com/lite/", nil, payload)
-		if err != nil {
-			return
+	pager := a.textPager(BackendLite, keywords, payload)
+	if maxResults > 0 {
+		maxResults = lo.Min([]int{maxResults, MaxTextResults})
+		pager.offsets = append(pager.offsets, rangeFunc(23, maxResults, 50)...)
+	}
+	return pager, nil
+}
+
+/**
+* Where each text backend is fetched from, how its pages are parsed and how
+* the server's link to the next page, spelling correction and related
+* searches are found.
+**/
+type textBackend struct {
+	method   string
+	host     string
+	path     string
+	parse    func(body []byte, keywords string) ([]TextResult, error)
+	next     func(body []byte, keywords string) map[string]string
+	spelling func(body []byte) *Spelling
+	related  func(body []byte) []string
+}
+
+var textBackends = map[Backend]textBackend{
+	BackendAPI: {
+		method:   "GET",
+		host:     "links.duckduckgo.com",
+		path:     "/d.js",
+		parse:    parseTextAPI,
+		next:     nextTextAPI,
+		spelling: spellingTextAPI,
+		related:  relatedTextAPI,
+	},
+	BackendHTML: {
+		method:   "POST",
+		host:     "html.duckduckgo.com",
+		path:     "/html",
+		parse:    parseTextHTML,
+		next:     nextTextForm,
+		spelling: spellingTextHTML,
+		related:  relatedTextHTML,
+	},
+	BackendLite: {
+		method:   "POST",
+		host:     "lite.duckduckgo.com",
+		path:     "/lite/",
+		parse:    parseTextLite,
+		next:     nextTextForm,
+		spelling: spellingTextHTML,
+		related:  relatedTextHTML,
+	},
+}
+
+func (a *AsyncDDGS) textPager(backend Backend, keywords string, payload map[string]string) paginator[TextResult] {
+	b := textBackends[backend]
+	return paginator[TextResult]{
+		method:   b.method,
+		endpoint: a.url(b.host, b.path),
+		params:   payload,
+		offsets:  []int{0},
+		parse: func(body []byte) ([]TextResult, error) {
+			return b.parse(body, keywords)
+		},
+		key: func(result TextResult) string { return result.Href },
+	}
+}
+
+/**
+* text(backend="api") -> results of one page.
+**/
+func parseTextAPI(body []byte, keywords string) ([]TextResult, error) {
+	pageData, err := textExtractJSON(body, keywords)
+	if err != nil {
+		return nil, err
+	}
+	var results []TextResult
+	for _, row := range pageData {
+		href, _ := row["u"].(string)
+		if href == "" || href == fmt.Sprintf("http://www.google.com/search?q=%s", keywords) {
+			continue
 		}
-		if bytes.Contains(respContent, []byte("No more results.")) {
-			return
+		snippet, _ := row["a"].(string)
+		snippet = normalize(snippet)
+		if snippet == "" {
+			continue
 		}
-		tree, err := htmlquery.Parse(bytes.NewReader(respContent))
-		if err != nil {
-			return
+		title, _ := row["t"].(string)
+		displayURL, _ := row["d"].(string)
+		domain, _ := row["i"].(string)
+		result := TextResult{
+			Title:      normalize(title),
+			Href:       normalizeURL(href),
+			Body:       snippet,
+			DisplayURL: displayURL,
+			Favicon:    faviconURL(domain, href),
 		}
-		var title string
-		var body string
-		var href string
-		data := htmlquery.Find(tree, "//table[last()]//tr")
-		for i, e := range data {
-			if i == 0 {
-				htmlHref := htmlquery.FindOne(e, ".//a//@href")
-				if htmlHref != nil {
-					href = htmlquery.InnerText(htmlHref)
-					if href == "" || cache[href] || strings.HasPrefix(href, "http://www.google.com/search?q=") || strings.HasPrefix(href, "https://duckduckgo.com/y.js?ad_domain") {
-						for range [3]struct{}{} { // skip block(i=1,2,3,4)
-							_, _ = next(data, nil)
-						}
-					} else {
-						cache[href] = true
-						title = htmlquery.InnerText(htmlquery.FindOne(e, ".//a//text()"))
-					}
-				}
-			} else if i == 1 {
-				htmlbody := htmlquery.Find(e, ".//td[@class='result-snippet']//text()")
-				body = strings.Join(lo.Map(htmlbody, func(d *html.Node, _ int) string { return d.Data }), "")
-			} else if i == 2 {
-				priority++
-				result := map[string]string{
-					"title": normalize(title),
-					"href":  normalizeURL(href),
-					"body":  normalize(body),
-				}
-				mu.Lock()
-				results[priority] = result
-				mu.Unlock()
+		if date, _ := row["e"].(string); date != "" {
+			if published := parseTimestamp(date); !published.IsZero() {
+				result.Published = &published
 			}
 		}
+		links, _ := row["l"].([]any)
+		for _, link := range links {
+			link, _ := link.(map[string]any)
+			linkURL, _ := link["targetUrl"].(string)
+			if linkURL == "" {
+				continue
+			}
+			linkTitle, _ := link["text"].(string)
+			linkSnippet, _ := link["snippet"].(string)
+			result.Sitelinks = append(result.Sitelinks, Sitelink{
+				Title:   normalize(linkTitle),
+				URL:     normalizeURL(linkURL),
+				Snippet: normalize(linkSnippet),
+			})
+		}
+		results = append(results, result)
 	}
-	wg.Add(1)
-	go textLitePage(0, 0)
-	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 500})
-		for i, s := range rangeFunc(23, maxResults, 50) {
-			wg.Add(1)
-			go textLitePage(s, i+1)
+	return results, nil
+}
+
+/**
+* text(backend="html") -> results of one page.
+**/
+func parseTextHTML(body []byte, _ string) ([]TextResult, error) {
+	if bytes.Contains(body, []byte("No  results.")) {
+		return nil, nil
+	}
+	tree, err := htmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var results []TextResult
+	for _, e := range htmlquery.Find(tree, "//div[h2]") {
+		hrefNode := htmlquery.FindOne(e, "./a/@href")
+		if hrefNode == nil {
+			continue
+		}
+		href := htmlquery.InnerText(hrefNode)
+		if href == "" || isSkippedHref(href) {
+			continue
 		}
+		title := htmlquery.FindOne(e, "./h2/a")
+		snippet := htmlquery.Find(e, "./a//text()")
+		result := TextResult{
+			Title:   normalize(htmlquery.InnerText(title)),
+			Href:    normalizeURL(href),
+			Body:    normalize(strings.Join(lo.Map(snippet, func(d *html.Node, _ int) string { return d.Data }), "")),
+			Favicon: faviconURL("", href),
+		}
+		if displayURL := htmlquery.FindOne(e, ".//a[contains(@class,'result__url')]"); displayURL != nil {
+			result.DisplayURL = strings.TrimSpace(normalize(htmlquery.InnerText(displayURL)))
+		}
+		results = append(results, result)
 	}
-	wg.Wait()
-	return lo.Slice(lo.Filter(results, func(res map[string]string, _ int) bool {
-		return res != nil
-	}), maxResults, 0), nil
+	return results, nil
 }
 
-func next(it []*html.Node, def *struct{}) (any, bool) {
-	if len(it) == 0 {
-		return def, false
+/**
+* text(backend="lite") -> results of one page. Every result spans four rows of
+* the last table: link, snippet, displayed url and a spacer.
+**/
+func parseTextLite(body []byte, _ string) ([]TextResult, error) {
+	if bytes.Contains(body, []byte("No more results.")) {
+		return nil, nil
 	}
-	v := (it)[0]
-	it = (it)[1:]
-	return &v, true
+	tree, err := htmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var results []TextResult
+	rows := htmlquery.Find(tree, "//table[last()]//tr")
+	for i := 0; i+1 < len(rows); i += 4 {
+		hrefNode := htmlquery.FindOne(rows[i], ".//a//@href")
+		if hrefNode == nil {
+			continue
+		}
+		href := htmlquery.InnerText(hrefNode)
+		if href == "" || isSkippedHref(href) {
+			continue
+		}
+		title := htmlquery.InnerText(htmlquery.FindOne(rows[i], ".//a"))
+		snippet := htmlquery.Find(rows[i+1], ".//td[@class='result-snippet']//text()")
+		result := TextResult{
+			Title:   normalize(title),
+			Href:    normalizeURL(href),
+			Body:    normalize(strings.Join(lo.Map(snippet, func(d *html.Node, _ int) string { return d.Data }), "")),
+			Favicon: faviconURL("", href),
+		}
+		if i+2 < len(rows) {
+			if displayURL := htmlquery.FindOne(rows[i+2], ".//span[@class='link-text']"); displayURL != nil {
+				result.DisplayURL = strings.TrimSpace(normalize(htmlquery.InnerText(displayURL)))
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
 
-/*
-*
-  - region: wt-wt, us-en, uk-en, ru-ru, etc. Defaults to "wt-wt".
-  - safesearch: on, moderate, off. Defaults to "moderate".
-  - timelimit: Day, Week, Month, Year. Defaults to None.
-  - size: Small, Medium, Large, Wallpaper. Defaults to None.
-  - color: color, Monochrome, Red, Orange, Yellow, Green, Blue,
-    Purple, Pink, Brown, Black, Gray, Teal, White. Defaults to None.
-  - type_image: photo, clipart, gif, transparent, line.
-    Defaults to None.
-  - layout: Square, Tall, Wide. Defaults to None.
+/**
+* Google fallbacks and ads.
+**/
+func isSkippedHref(href string) bool {
+	return strings.HasPrefix(href, "http://www.google.com/search?q=") || strings.HasPrefix(href, "https://duckduckgo.com/y.js?ad_domain")
+}
 
-*
-*/
-func (a *AsyncDDGS) Images(keywords string, region string, safesearch string, timelimit string, size string, color string, typeImage string, layout string, licenseImage string, maxResults int) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("keywords is mandatory")
-	}
+/**
+* Images searches images. See ImagesRequest for the available filters.
+* If some pages fail, the results of the others are returned together with
+* a *PartialResultsError, unless the client is strict.
+**/
+func (a *AsyncDDGS) Images(req ImagesRequest) ([]ImageResult, error) {
+	return a.ImagesContext(context.Background(), req)
+}
 
-	if region == "" {
-		region = "wt-wt"
-	}
-	if safesearch == "" {
-		safesearch = "moderate"
+// ImagesContext is like Images but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) ImagesContext(ctx context.Context, req ImagesRequest) ([]ImageResult, error) {
+	resp, err := a.SearchImages(ctx, req)
+	if resp == nil {
+		return nil, err
 	}
+	return resp.Results, err
+}
 
-	vqd, err := a.agetVqd(keywords)
-	if err != nil {
+/**
+* SearchImages is like ImagesContext but also reports how the results were obtained.
+**/
+func (a *AsyncDDGS) SearchImages(ctx context.Context, req ImagesRequest) (*ImagesResponse, error) {
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	req = req.withDefaults()
+	start := time.Now()
+	results, meta, err := searchPages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[ImageResult], error) {
+		return a.imagesPager(ctx, req)
+	})
+	meta.Region, meta.Elapsed = req.Region, time.Since(start)
+	return &ImagesResponse{Results: results, ResponseMeta: meta}, err
+}
 
-	safesearchBase := map[string]string{
-		"on":       "1",
-		"moderate": "1",
-		"off":      "-1",
+/**
+* ImagesIter is like ImagesContext but yields results in rank order as pages arrive.
+**/
+func (a *AsyncDDGS) ImagesIter(ctx context.Context, req ImagesRequest) *Iterator[ImageResult] {
+	if err := req.Validate(); err != nil {
+		return failedIterator[ImageResult](ctx, err)
+	}
+	req = req.withDefaults()
+	return iteratePages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[ImageResult], error) {
+		return a.imagesPager(ctx, req)
+	})
+}
+
+func (a *AsyncDDGS) imagesPager(ctx context.Context, req ImagesRequest) (paginator[ImageResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
+
+	vqd, err := a.agetVqd(ctx, keywords)
+	if err != nil {
+		return paginator[ImageResult]{}, err
 	}
 
 	payload := map[string]string{
-		"l":   region,
+		"l":   string(req.Region),
 		"o":   "json",
 		"q":   keywords,
 		"vqd": vqd,
-		"p":   safesearchBase[strings.ToLower(safesearch)],
+		"p":   req.SafeSearch.value(),
 	}
 
 	f := ""
-	if len(timelimit) > 0 {
-		f += "time:" + timelimit + ","
+	if req.TimeLimit != "" {
+		f += "time:" + req.TimeLimit.imagesValue() + ","
 	}
-	if len(size) > 0 {
-		f += "size:" + size + ","
+	if req.Size != "" {
+		f += "size:" + string(req.Size) + ","
 	}
-	if len(color) > 0 {
-		f += "color:" + color + ","
+	if req.Color != "" {
+		f += "color:" + string(req.Color) + ","
 	}
-	if len(typeImage) > 0 {
-		f += "type:" + typeImage + ","
+	if req.Type != "" {
+		f += "type:" + string(req.Type) + ","
 	}
-	if len(layout) > 0 {
-		f += "layout:" + layout + ","
+	if req.Layout != "" {
+		f += "layout:" + string(req.Layout) + ","
 	}
-	if len(licenseImage) > 0 {
-		f += "license:" + licenseImage
+	if req.License != "" {
+		f += "license:" + string(req.License)
 	}
 
 	if len(f) > 0 {
@@ -454,124 +815,117 @@ func (a *AsyncDDGS) Images(keywords string, region string, safesearch string, ti
 
 	}
 
-	cache := make(map[string]bool)
-	results := make([]map[string]string, 600)
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	imagesPage := func(s, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("GET", fmt.Sprintf("https://%s/i.js", a.host("duckduckgo.com")), nil, payload)
-		if err != nil {
-			return
-		}
-		var respJSON map[string]interface{}
-		err = json.Unmarshal(respContent, &respJSON)
-		if err != nil {
-			return
-		}
-		pageData, ok := respJSON["results"].([]interface{})
-		if !ok {
-			return
-		}
-
-		for _, row := range pageData {
-			rowMap, ok := row.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			imageURL, ok := rowMap["image"].(string)
-			if !ok || imageURL == "" {
-				continue
+	pager := paginator[ImageResult]{
+		method:   "GET",
+		endpoint: a.url("duckduckgo.com", "/i.js"),
+		params:   payload,
+		offsets:  []int{0},
+		parse: func(body []byte) ([]ImageResult, error) {
+			var respJSON imagesResponse
+			if err := json.Unmarshal(body, &respJSON); err != nil {
+				return nil, err
 			}
-
-			if _, ok := cache[imageURL]; !ok {
-				cache[imageURL] = true
-				priority++
-				result := map[string]string{
-					"title":     rowMap["title"].(string),
-					"image":     normalizeURL(imageURL),
-					"thumbnail": normalizeURL(rowMap["thumbnail"].(string)),
-					"url":       normalizeURL(rowMap["url"].(string)),
-					"height":    fmt.Sprintf("%v", rowMap["height"]),
-					"width":     fmt.Sprintf("%v", rowMap["width"]),
-					"source":    rowMap["source"].(string),
+			var results []ImageResult
+			for _, row := range respJSON.Results {
+				if row.Image == "" {
+					continue
 				}
-				mu.Lock()
-				results[priority] = result
-				mu.Unlock()
+				results = append(results, ImageResult{
+					Title:     row.Title,
+					Image:     normalizeURL(row.Image),
+					Thumbnail: normalizeURL(row.Thumbnail),
+					URL:       normalizeURL(row.URL),
+					Height:    int(row.Height),
+					Width:     int(row.Width),
+					Source:    row.Source,
+				})
 			}
-		}
+			return results, nil
+		},
+		key: func(result ImageResult) string { return result.Image },
 	}
-
-	wg.Add(1)
-	go imagesPage(0, 0)
 	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 500})
-		for i, s := range rangeFunc(100, maxResults, 100) {
-			wg.Add(1)
-			go imagesPage(s, i+1)
-		}
+		maxResults = lo.Min([]int{maxResults, MaxImagesResults})
+		pager.offsets = append(pager.offsets, rangeFunc(100, maxResults, 100)...)
 	}
-	wg.Wait()
+	return pager, nil
+}
 
-	return lo.Slice(lo.Filter(results, func(res map[string]string, _ int) bool {
-		return res != nil
-	}), 0, maxResults), nil
+/**
+* Videos searches videos. See VideosRequest for the available filters.
+* If some pages fail, the results of the others are returned together with
+* a *PartialResultsError, unless the client is strict.
+**/
+func (a *AsyncDDGS) Videos(req VideosRequest) ([]VideoResult, error) {
+	return a.VideosContext(context.Background(), req)
 }
 
-// region: wt-wt, us-en, uk-en, ru-ru, etc. Defaults to "wt-wt".
-// safesearch: on, moderate, off. Defaults to "moderate".
-// timelimit: d, w, m. Defaults to None.
-// resolution: high, standart. Defaults to None.
-// duration: short, medium, long. Defaults to None.
-// license_videos: creativeCommon, youtube. Defaults to None.
-func (a *AsyncDDGS) Videos(keywords string, region string, safesearch string, timelimit string, resolution string, duration string, licenseVideos string, maxResults int) ([]map[string]interface{}, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("keywords is mandatory")
+// VideosContext is like Videos but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) VideosContext(ctx context.Context, req VideosRequest) ([]VideoResult, error) {
+	resp, err := a.SearchVideos(ctx, req)
+	if resp == nil {
+		return nil, err
 	}
+	return resp.Results, err
+}
 
-	if region == "" {
-		region = "wt-wt"
-	}
-	if safesearch == "" {
-		safesearch = "moderate"
+/**
+* SearchVideos is like VideosContext but also reports how the results were obtained.
+**/
+func (a *AsyncDDGS) SearchVideos(ctx context.Context, req VideosRequest) (*VideosResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
+	req = req.withDefaults()
+	start := time.Now()
+	results, meta, err := searchPages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[VideoResult], error) {
+		return a.videosPager(ctx, req)
+	})
+	meta.Region, meta.Elapsed = req.Region, time.Since(start)
+	return &VideosResponse{Results: results, ResponseMeta: meta}, err
+}
 
-	vqd, err := a.agetVqd(keywords)
-	if err != nil {
-		return nil, err
+/**
+* VideosIter is like VideosContext but yields results in rank order as pages arrive.
+**/
+func (a *AsyncDDGS) VideosIter(ctx context.Context, req VideosRequest) *Iterator[VideoResult] {
+	if err := req.Validate(); err != nil {
+		return failedIterator[VideoResult](ctx, err)
 	}
+	req = req.withDefaults()
+	return iteratePages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[VideoResult], error) {
+		return a.videosPager(ctx, req)
+	})
+}
+
+func (a *AsyncDDGS) videosPager(ctx context.Context, req VideosRequest) (paginator[VideoResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
 
-	safesearchBase := map[string]string{
-		"on":       "1",
-		"moderate": "-1",
-		"off":      "-2",
+	vqd, err := a.agetVqd(ctx, keywords)
+	if err != nil {
+		return paginator[VideoResult]{}, err
 	}
 
 	payload := map[string]string{
-		"l":   region,
+		"l":   string(req.Region),
 		"o":   "json",
 		"q":   keywords,
 		"vqd": vqd,
-		"p":   safesearchBase[strings.ToLower(safesearch)],
+		"p":   req.SafeSearch.value(),
 	}
 
 	f := ""
-	if len(timelimit) > 0 {
-		f += "publishedAfter:" + timelimit + ","
+	if req.TimeLimit != "" {
+		f += "publishedAfter:" + string(req.TimeLimit) + ","
 	}
-	if len(resolution) > 0 {
-		f += "videoDefinition:" + resolution + ","
+	if req.Resolution != "" {
+		f += "videoDefinition:" + string(req.Resolution) + ","
 	}
-	if len(duration) > 0 {
-		f += "videoDuration:" + duration + ","
+	if req.Duration != "" {
+		f += "videoDuration:" + string(req.Duration) + ","
 	}
-	if len(licenseVideos) > 0 {
-		f += "videoLicense:" + licenseVideos + ","
+	if req.License != "" {
+		f += "videoLicense:" + string(req.License) + ","
 	}
 
 	if len(f) > 0 {
@@ -579,249 +933,310 @@ func (a *AsyncDDGS) Videos(keywords string, region string, safesearch string, ti
 
 	}
 
-	cache := make(map[string]bool)
-	results := make([]map[string]interface{}, 700)
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	videosPage := func(s, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("GET", fmt.Sprintf("https://%s/v.js", a.host("duckduckgo.com")), nil, payload)
-		if err != nil {
-			return
-		}
-		var respJSON map[string]interface{}
-		err = json.Unmarshal(respContent, &respJSON)
-		if err != nil {
-			return
-		}
-		pageData, ok := respJSON["results"].([]interface{})
-		if !ok {
-			return
-		}
-
-		for _, row := range pageData {
-			rowMap, ok := row.(map[string]interface{})
-			if !ok {
-				continue
+	pager := paginator[VideoResult]{
+		method:   "GET",
+		endpoint: a.url("duckduckgo.com", "/v.js"),
+		params:   payload,
+		offsets:  []int{0},
+		parse: func(body []byte) ([]VideoResult, error) {
+			var respJSON videosResponse
+			if err := json.Unmarshal(body, &respJSON); err != nil {
+				return nil, err
 			}
-
-			content, ok := rowMap["content"].(string)
-			if !ok || content == "" {
-				continue
-			}
-
-			if _, ok := cache[content]; !ok {
-				cache[content] = true
-				priority++
-				mu.Lock()
-				results[priority] = rowMap
-				mu.Unlock()
+			var results []VideoResult
+			for _, row := range respJSON.Results {
+				if row.Content == "" {
+					continue
+				}
+				results = append(results, VideoResult{
+					Content:     row.Content,
+					Title:       row.Title,
+					Description: row.Description,
+					Duration:    parseClipDuration(row.Duration),
+					EmbedHTML:   row.EmbedHTML,
+					EmbedURL:    row.EmbedURL,
+					ImageToken:  row.ImageToken,
+					Images:      row.Images,
+					Provider:    row.Provider,
+					Published:   parseTimestamp(row.Published),
+					Publisher:   row.Publisher,
+					Statistics:  row.Statistics,
+					Uploader:    row.Uploader,
+				})
 			}
-		}
+			return results, nil
+		},
+		key: func(result VideoResult) string { return result.Content },
 	}
-
-	wg.Add(1)
-	go videosPage(0, 0)
 	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 400})
-		for i, s := range rangeFunc(59, maxResults, 59) {
-			wg.Add(1)
-			go videosPage(s, i+1)
-		}
+		maxResults = lo.Min([]int{maxResults, MaxVideosResults})
+		pager.offsets = append(pager.offsets, rangeFunc(59, maxResults, 59)...)
 	}
-	wg.Wait()
+	return pager, nil
+}
 
-	return lo.Slice(lo.Filter(results, func(res map[string]interface{}, _ int) bool {
-		return res != nil
-	}), 0, maxResults), nil
+/**
+* News searches news articles. See NewsRequest for the available parameters.
+* If some pages fail, the results of the others are returned together with
+* a *PartialResultsError, unless the client is strict.
+**/
+func (a *AsyncDDGS) News(req NewsRequest) ([]NewsResult, error) {
+	return a.NewsContext(context.Background(), req)
 }
 
-/*
-*
-  - region: wt-wt, us-en, uk-en, ru-ru, etc. Defaults to "wt-wt".
-    safesearch: on, moderate, off. Defaults to "moderate".
-    timelimit: d, w, m. Defaults to None.
-*/
-func (a *AsyncDDGS) News(keywords string, region string, safesearch string, timelimit string, maxResults int) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("keywords is mandatory")
+// NewsContext is like News but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) NewsContext(ctx context.Context, req NewsRequest) ([]NewsResult, error) {
+	resp, err := a.SearchNews(ctx, req)
+	if resp == nil {
+		return nil, err
 	}
+	return resp.Results, err
+}
 
-	if region == "" {
-		region = "wt-wt"
-	}
-	if safesearch == "" {
-		safesearch = "moderate"
+/**
+* SearchNews is like NewsContext but also reports how the results were obtained.
+**/
+func (a *AsyncDDGS) SearchNews(ctx context.Context, req NewsRequest) (*NewsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
+	req = req.withDefaults()
+	start := time.Now()
+	results, meta, err := searchPages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[NewsResult], error) {
+		return a.newsPager(ctx, req)
+	})
+	meta.Region, meta.Elapsed = req.Region, time.Since(start)
+	return &NewsResponse{Results: results, ResponseMeta: meta}, err
+}
 
-	vqd, err := a.agetVqd(keywords)
-	if err != nil {
-		return nil, err
+/**
+* NewsIter is like NewsContext but yields results in rank order as pages arrive.
+**/
+func (a *AsyncDDGS) NewsIter(ctx context.Context, req NewsRequest) *Iterator[NewsResult] {
+	if err := req.Validate(); err != nil {
+		return failedIterator[NewsResult](ctx, err)
 	}
+	req = req.withDefaults()
+	return iteratePages(ctx, a, req.MaxResults, func(ctx context.Context) (paginator[NewsResult], error) {
+		return a.newsPager(ctx, req)
+	})
+}
+
+func (a *AsyncDDGS) newsPager(ctx context.Context, req NewsRequest) (paginator[NewsResult], error) {
+	keywords, maxResults := req.Keywords, req.MaxResults
 
-	safesearchBase := map[string]string{
-		"on":       "1",
-		"moderate": "-1",
-		"off":      "-2",
+	vqd, err := a.agetVqd(ctx, keywords)
+	if err != nil {
+		return paginator[NewsResult]{}, err
 	}
 
 	payload := map[string]string{
-		"l":     region,
+		"l":     string(req.Region),
 		"o":     "json",
 		"noamp": "1",
 		"q":     keywords,
 		"vqd":   vqd,
-		"p":     safesearchBase[strings.ToLower(safesearch)],
+		"p":     req.SafeSearch.value(),
 	}
 
-	if timelimit != "" {
-		payload["df"] = timelimit
+	if req.TimeLimit != "" {
+		payload["df"] = string(req.TimeLimit)
 	}
 
-	cache := make(map[string]bool)
-	results := make([]map[string]string, 700)
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	newsPage := func(s, page int) {
-		defer wg.Done()
-		priority := page * 100
-		payload["s"] = fmt.Sprintf("%d", s)
-		respContent, err := a.agetURL("GET", fmt.Sprintf("https://%s/news.js", a.host("duckduckgo.com")), nil, payload)
-		if err != nil {
-			return
-		}
-		var respJSON map[string]interface{}
-		err = json.Unmarshal(respContent, &respJSON)
-		if err != nil {
-			return
-		}
-		pageData, ok := respJSON["results"].([]interface{})
-		if !ok {
-			return
-		}
-
-		for _, row := range pageData {
-			rowMap, ok := row.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			url, ok := rowMap["url"].(string)
-			if !ok || url == "" {
-				continue
+	pager := paginator[NewsResult]{
+		method:   "GET",
+		endpoint: a.url("duckduckgo.com", "/news.js"),
+		params:   payload,
+		offsets:  []int{0},
+		parse: func(body []byte) ([]NewsResult, error) {
+			var respJSON newsResponse
+			if err := json.Unmarshal(body, &respJSON); err != nil {
+				return nil, err
 			}
-
-			if _, ok := cache[url]; !ok {
-				cache[url] = true
-				imageURL, _ := rowMap["image"].(string)
-				priority++
-				result := map[string]string{
-					"date":   time.Unix(int64(rowMap["date"].(float64)), 0).UTC().Format(time.RFC3339),
-					"title":  rowMap["title"].(string),
-					"body":   normalize(rowMap["excerpt"].(string)),
-					"url":    normalizeURL(url),
-					"image":  normalizeURL(imageURL),
-					"source": rowMap["source"].(string),
+			var results []NewsResult
+			for _, row := range respJSON.Results {
+				if row.URL == "" {
+					continue
 				}
-				mu.Lock()
-				results[priority] = result
-				mu.Unlock()
+				results = append(results, NewsResult{
+					Date:   time.Unix(int64(row.Date), 0).UTC(),
+					Title:  row.Title,
+					Body:   normalize(row.Excerpt),
+					URL:    normalizeURL(row.URL),
+					Image:  normalizeURL(row.Image),
+					Source: row.Source,
+				})
 			}
-		}
+			return results, nil
+		},
+		key: func(result NewsResult) string { return result.URL },
 	}
-	wg.Add(1)
-	go newsPage(0, 0)
 	if maxResults > 0 {
-		maxResults = lo.Min([]int{maxResults, 400})
-		for i, s := range rangeFunc(59, maxResults, 59) {
-			wg.Add(1)
-			go newsPage(s, i+1)
-		}
+		maxResults = lo.Min([]int{maxResults, MaxNewsResults})
+		pager.offsets = append(pager.offsets, rangeFunc(59, maxResults, 59)...)
 	}
-	wg.Wait()
-
-	return lo.Slice(lo.Filter(results, func(res map[string]string, _ int) bool {
-		return res != nil
-	}), 0, maxResults), nil
+	return pager, nil
 }
 
-func (a *AsyncDDGS) Answers(keywords string) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("keywords is mandatory")
-	}
+func (a *AsyncDDGS) Answers(keywords string) ([]AnswerResult, error) {
+	return a.AnswersContext(context.Background(), keywords)
+}
 
-	payload := map[string]string{
-		"format": "json",
-		"q":      fmt.Sprintf("what is %s", keywords),
+// AnswersContext is like Answers but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) AnswersContext(ctx context.Context, keywords string) ([]AnswerResult, error) {
+	if err := validateKeywords(keywords); err != nil {
+		return nil, err
 	}
 
-	respContent, err := a.agetURL("GET", "https://api.duckduckgo.com/", nil, payload)
+	abstract, err := a.InstantAnswer(ctx, InstantAnswerRequest{Keywords: keywords, WhatIs: true})
 	if err != nil {
 		return nil, err
 	}
+	results := []AnswerResult{}
+	if abstract.AbstractText != "" {
+		results = append(results, AnswerResult{
+			Text: abstract.AbstractText,
+			URL:  abstract.AbstractURL,
+		})
+	}
 
-	var pageData map[string]interface{}
-	err = json.Unmarshal(respContent, &pageData)
+	related, err := a.InstantAnswer(ctx, InstantAnswerRequest{Keywords: keywords})
 	if err != nil {
 		return nil, err
 	}
-
-	results := []map[string]string{}
-	answer, _ := pageData["AbstractText"].(string)
-	url, _ := pageData["AbstractURL"].(string)
-	if answer != "" {
-		results = append(results, map[string]string{
-			"icon":  "",
-			"text":  answer,
-			"topic": "",
-			"url":   url,
+	for _, topic := range related.RelatedTopics {
+		results = append(results, AnswerResult{
+			Icon: topic.Icon,
+			Text: topic.Text,
+			URL:  topic.URL,
 		})
 	}
+	for _, group := range related.Groups {
+		for _, topic := range group.Topics {
+			results = append(results, AnswerResult{
+				Icon:  topic.Icon,
+				Text:  topic.Text,
+				Topic: group.Name,
+				URL:   topic.URL,
+			})
+		}
+	}
 
-	payload["q"] = keywords
+	return results, nil
+}
 
-	respContent, err = a.agetURL("GET", "https://api.duckduckgo.com/", nil, payload)
-	if err != nil {
+/**
+* InstantAnswer looks keywords up in the Instant Answer API (api.duckduckgo.com)
+* and returns its whole response.
+**/
+func (a *AsyncDDGS) InstantAnswer(ctx context.Context, req InstantAnswerRequest) (*InstantAnswer, error) {
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(respContent, &pageData)
+	payload := map[string]string{
+		"format": "json",
+		"q":      req.Keywords,
+	}
+	if req.WhatIs {
+		payload["q"] = fmt.Sprintf("what is %s", req.Keywords)
+	}
+	if req.SkipDisambiguation {
+		payload["skip_disambig"] = "1"
+	}
+
+	respContent, err := a.agetURL(ctx, "GET", a.url("api.duckduckgo.com", "/"), nil, payload)
 	if err != nil {
 		return nil, err
 	}
+	return parseInstantAnswer(respContent)
+}
 
-	pageDataList, _ := pageData["RelatedTopics"].([]interface{})
-	for _, row := range pageDataList {
-		rowMap, _ := row.(map[string]interface{})
-		topic, _ := rowMap["Name"].(string)
-		if topic == "" {
-			icon, _ := rowMap["Icon"].(map[string]interface{})
-			results = append(results, map[string]string{
-				"icon":  fmt.Sprintf("https://duckduckgo.com%s", icon["URL"].(string)),
-				"text":  rowMap["Text"].(string),
-				"topic": "",
-				"url":   rowMap["FirstURL"].(string),
-			})
-		} else {
-			subRowList, _ := rowMap["Topics"].([]interface{})
-			for _, subRow := range subRowList {
-				subRowMap, _ := subRow.(map[string]interface{})
-				icon, _ := subRowMap["Icon"].(map[string]interface{})
-				results = append(results, map[string]string{
-					"icon":  fmt.Sprintf("https://duckduckgo.com%s", icon["URL"].(string)),
-					"text":  subRowMap["Text"].(string),
-					"topic": topic,
-					"url":   subRowMap["FirstURL"].(string),
-				})
-			}
+func parseInstantAnswer(body []byte) (*InstantAnswer, error) {
+	var row answersResponse
+	if err := json.Unmarshal(body, &row); err != nil {
+		return nil, err
+	}
+	answer := &InstantAnswer{
+		Type:             InstantAnswerType(row.Type),
+		Heading:          row.Heading,
+		Entity:           row.Entity,
+		Abstract:         row.Abstract,
+		AbstractText:     row.AbstractText,
+		AbstractSource:   row.AbstractSource,
+		AbstractURL:      row.AbstractURL,
+		Image:            answersURL(row.Image),
+		ImageWidth:       int(rawNumber(row.ImageWidth)),
+		ImageHeight:      int(rawNumber(row.ImageHeight)),
+		ImageIsLogo:      rawNumber(row.ImageIsLogo) != 0,
+		Answer:           rawText(row.Answer),
+		AnswerType:       row.AnswerType,
+		Definition:       row.Definition,
+		DefinitionSource: row.DefinitionSource,
+		DefinitionURL:    row.DefinitionURL,
+		Redirect:         row.Redirect,
+		Results:          answersTopics(row.Results),
+	}
+
+	for _, topic := range row.RelatedTopics {
+		if topic.Name == "" {
+			answer.RelatedTopics = append(answer.RelatedTopics, answersTopics([]answersTopic{topic})...)
+			continue
 		}
+		answer.Groups = append(answer.Groups, AnswerGroup{
+			Name:   topic.Name,
+			Topics: answersTopics(topic.Topics),
+		})
 	}
 
-	return results, nil
+	// Infobox is "" when there is none.
+	if bytes.HasPrefix(bytes.TrimSpace(row.Infobox), []byte("{")) {
+		var infobox answersInfobox
+		if err := json.Unmarshal(row.Infobox, &infobox); err != nil {
+			return nil, err
+		}
+		answer.Infobox = &Infobox{
+			Content: infoboxItems(infobox.Content),
+			Meta:    infoboxItems(infobox.Meta),
+		}
+	}
+	return answer, nil
+}
+
+func answersTopics(rows []answersTopic) []AnswerTopic {
+	topics := make([]AnswerTopic, 0, len(rows))
+	for _, row := range rows {
+		topics = append(topics, AnswerTopic{
+			Text:   row.Text,
+			URL:    row.FirstURL,
+			Icon:   answersURL(row.Icon.URL),
+			Result: row.Result,
+		})
+	}
+	return topics
+}
+
+func infoboxItems(rows []answersInfoboxItem) []InfoboxItem {
+	items := make([]InfoboxItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, InfoboxItem{
+			Label:     row.Label,
+			Value:     rawText(row.Value),
+			DataType:  row.DataType,
+			WikiOrder: int(rawNumber(row.WikiOrder)),
+		})
+	}
+	return items
+}
+
+/**
+* Icons and images of the Instant Answer API are relative to duckduckgo.com.
+**/
+func answersURL(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return "https://duckduckgo.com" + path
+	}
+	return path
 }
 
 /**
@@ -829,25 +1244,27 @@ func (a *AsyncDDGS) Answers(keywords string) ([]map[string]string, error) {
 *   region: wt-wt, us-en, uk-en, ru-ru, etc. Defaults to "wt-wt".
 *
  */
-func (a *AsyncDDGS) Suggestions(keywords string, region string) ([]map[string]string, error) {
-	if keywords == "" {
-		return nil, fmt.Errorf("keywords is mandatory")
-	}
-	if region == "" {
-		region = "wt-wt"
+func (a *AsyncDDGS) Suggestions(keywords string, region Region) ([]Suggestion, error) {
+	return a.SuggestionsContext(context.Background(), keywords, region)
+}
+
+// SuggestionsContext is like Suggestions but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) SuggestionsContext(ctx context.Context, keywords string, region Region) ([]Suggestion, error) {
+	if err := firstError(validateKeywords(keywords), validateRegion(region)); err != nil {
+		return nil, err
 	}
 
 	payload := map[string]string{
 		"q":  keywords,
-		"kl": region,
+		"kl": string(defaultRegion(region)),
 	}
 
-	respContent, err := a.agetURL("GET", fmt.Sprintf("https://%s/ac", a.host("duckduckgo.com")), nil, payload)
+	respContent, err := a.agetURL(ctx, "GET", a.url("duckduckgo.com", "/ac"), nil, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	var pageData []map[string]string
+	var pageData []Suggestion
 	err = json.Unmarshal(respContent, &pageData)
 	if err != nil {
 		return nil, err
@@ -865,8 +1282,16 @@ func (a *AsyncDDGS) Suggestions(keywords string, region string) ([]map[string]st
 *   	   - fr
 *   	   - ja
 *   	   - ko
+*
+* When some keywords fail, the other translations are returned with a
+* *PartialTranslationError naming the failed keywords, unless the client is strict.
 **/
 func (a *AsyncDDGS) Translate(keywords []string, from string, to string) (map[string]string, error) {
+	return a.TranslateContext(context.Background(), keywords, from, to)
+}
+
+// TranslateContext is like Translate but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) TranslateContext(ctx context.Context, keywords []string, from string, to string) (map[string]string, error) {
 	if len(keywords) == 0 {
 		return nil, fmt.Errorf("Keywords is mandatory")
 	}
@@ -874,7 +1299,7 @@ func (a *AsyncDDGS) Translate(keywords []string, from string, to string) (map[st
 		to = "en"
 	}
 
-	vqd, err := a.agetVqd("translate")
+	vqd, err := a.agetVqd(ctx, "translate")
 	if err != nil {
 		return nil, err
 	}
@@ -889,15 +1314,19 @@ func (a *AsyncDDGS) Translate(keywords []string, from string, to string) (map[st
 	}
 
 	var wg sync.WaitGroup
+	pageErrs := &pageErrors{}
+	endpoint := a.url("duckduckgo.com", "/translation.js")
 	var m sync.Map
-	translateKeyword := func(s string) {
+	translateKeyword := func(i int, s string) {
 		defer wg.Done()
-		respContent, err := a.agetURL("POST", fmt.Sprintf("https://%s/translation.js", a.host("duckduckgo.com")), []byte(s), payload)
+		respContent, err := a.agetURL(ctx, "POST", endpoint, []byte(s), payload)
 		if err != nil {
+			pageErrs.add(i, endpoint, err)
 			return
 		}
 		var pageData map[string]interface{}
 		if err = json.Unmarshal(respContent, &pageData); err != nil {
+			pageErrs.add(i, endpoint, err)
 			return
 		}
 
@@ -905,12 +1334,15 @@ func (a *AsyncDDGS) Translate(keywords []string, from string, to string) (map[st
 			m.Store(s, t)
 		}
 	}
-	for _, keyword := range keywords {
+	for i, keyword := range keywords {
 		wg.Add(1)
-		go translateKeyword(keyword)
+		go translateKeyword(i, keyword)
 
 	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	result := make(map[string]string)
 	m.Range(func(k, v interface{}) bool {
@@ -918,5 +1350,16 @@ func (a *AsyncDDGS) Translate(keywords []string, from string, to string) (map[st
 		return true
 	})
 
+	if pageErr := pageErrs.err(len(keywords)); pageErr != nil {
+		// The keywords were collected like pages, with the keyword index as offset.
+		err := &PartialTranslationError{Keywords: len(keywords)}
+		for _, pageErr := range pageErr.(*PartialResultsError).Failed {
+			err.Failed = append(err.Failed, &TranslateError{Keyword: keywords[pageErr.Offset], Endpoint: pageErr.Endpoint, Err: pageErr.Err})
+		}
+		if a.strict {
+			return nil, err
+		}
+		return result, err
+	}
 	return result, nil
 }
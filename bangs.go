@@ -0,0 +1,226 @@
+package duckduckgo
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/**
+* A hand-picked set of popular bangs in the format of duckduckgo.com/bang.js,
+* used by DefaultBangs. It carries no ranks: all of them are 0.
+**/
+//go:embed bangs.json
+var bangsSnapshot []byte
+
+/**
+* Bang is a "!trigger" shortcut that sends a query to another site. URL holds
+* the search URL with "{{{s}}}" in place of the query.
+**/
+type Bang struct {
+	Trigger     string `json:"trigger"`
+	Name        string `json:"name"`
+	Domain      string `json:"domain"`
+	Category    string `json:"category"`
+	Subcategory string `json:"subcategory"`
+	URL         string `json:"url"`
+	Rank        int    `json:"rank"`
+}
+
+/**
+* A row of bang.js.
+**/
+type bangRow struct {
+	Trigger     string `json:"t"`
+	Name        string `json:"s"`
+	Domain      string `json:"d"`
+	Category    string `json:"c"`
+	Subcategory string `json:"sc"`
+	URL         string `json:"u"`
+	Rank        int    `json:"r"`
+}
+
+/**
+* BangCatalog is a set of bangs that queries can be resolved against. It is
+* safe for concurrent use.
+**/
+type BangCatalog struct {
+	bangs     []Bang
+	byTrigger map[string]int
+}
+
+/**
+* NewBangCatalog builds a catalog of bangs. When several bangs share a
+* trigger, the first one wins.
+**/
+func NewBangCatalog(bangs []Bang) *BangCatalog {
+	c := &BangCatalog{
+		bangs:     append([]Bang(nil), bangs...),
+		byTrigger: make(map[string]int, len(bangs)),
+	}
+	for i, bang := range c.bangs {
+		trigger := strings.ToLower(bang.Trigger)
+		if _, ok := c.byTrigger[trigger]; !ok && trigger != "" {
+			c.byTrigger[trigger] = i
+		}
+	}
+	return c
+}
+
+/**
+* ParseBangs builds a catalog from a bang list in the format of
+* duckduckgo.com/bang.js.
+**/
+func ParseBangs(data []byte) (*BangCatalog, error) {
+	var rows []bangRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("invalid bang list: %w", err)
+	}
+	bangs := make([]Bang, 0, len(rows))
+	for _, row := range rows {
+		bangs = append(bangs, Bang(row))
+	}
+	return NewBangCatalog(bangs), nil
+}
+
+/**
+* LoadBangsFile builds a catalog from a local copy of duckduckgo.com/bang.js.
+**/
+func LoadBangsFile(path string) (*BangCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBangs(data)
+}
+
+var (
+	defaultBangsOnce sync.Once
+	defaultBangs     *BangCatalog
+)
+
+/**
+* DefaultBangs returns a small catalog of popular bangs embedded in the
+* library, without ranks. Use FetchBangs for the complete, ranked list.
+**/
+func DefaultBangs() *BangCatalog {
+	defaultBangsOnce.Do(func() {
+		// bangs_test.go checks that the snapshot parses.
+		defaultBangs, _ = ParseBangs(bangsSnapshot)
+		if defaultBangs == nil {
+			defaultBangs = NewBangCatalog(nil)
+		}
+	})
+	return defaultBangs
+}
+
+/**
+* FetchBangs downloads the live bang list from duckduckgo.com/bang.js.
+**/
+func (a *AsyncDDGS) FetchBangs(ctx context.Context) (*BangCatalog, error) {
+	respContent, err := a.agetURL(ctx, "GET", a.url("duckduckgo.com", "/bang.js"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBangs(respContent)
+}
+
+/**
+* All returns every bang of the catalog.
+**/
+func (c *BangCatalog) All() []Bang {
+	return append([]Bang(nil), c.bangs...)
+}
+
+/**
+* Lookup finds the bang with trigger, with or without its "!", ignoring case.
+**/
+func (c *BangCatalog) Lookup(trigger string) (Bang, bool) {
+	i, ok := c.byTrigger[strings.ToLower(strings.TrimPrefix(trigger, "!"))]
+	if !ok {
+		return Bang{}, false
+	}
+	return c.bangs[i], true
+}
+
+/**
+* ByCategory returns the bangs whose category or subcategory is category,
+* ignoring case, best ranked first and otherwise in catalog order.
+**/
+func (c *BangCatalog) ByCategory(category string) []Bang {
+	return c.filter(func(bang Bang) bool {
+		return strings.EqualFold(bang.Category, category) || strings.EqualFold(bang.Subcategory, category)
+	})
+}
+
+/**
+* ByDomain returns the bangs that search domain, with or without "www.",
+* best ranked first and otherwise in catalog order.
+**/
+func (c *BangCatalog) ByDomain(domain string) []Bang {
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	return c.filter(func(bang Bang) bool {
+		return strings.TrimPrefix(strings.ToLower(bang.Domain), "www.") == domain
+	})
+}
+
+func (c *BangCatalog) filter(match func(bang Bang) bool) []Bang {
+	var bangs []Bang
+	for _, bang := range c.bangs {
+		if match(bang) {
+			bangs = append(bangs, bang)
+		}
+	}
+	sort.SliceStable(bangs, func(i, j int) bool { return bangs[i].Rank > bangs[j].Rank })
+	return bangs
+}
+
+/**
+* BangResolution is a query routed by a bang. Query is the query without the
+* bang, and URL the page it leads to.
+**/
+type BangResolution struct {
+	Bang  Bang
+	Query string
+	URL   string
+}
+
+/**
+* Resolve finds the first known "!trigger" word of query, anywhere in it, and
+* returns where DuckDuckGo would redirect it. A bang without a query leads to
+* the home page of its site. Resolve makes no request.
+**/
+func (c *BangCatalog) Resolve(query string) (BangResolution, bool) {
+	words := strings.Fields(query)
+	for i, word := range words {
+		if len(word) < 2 || word[0] != '!' {
+			continue
+		}
+		bang, ok := c.Lookup(word)
+		if !ok {
+			continue
+		}
+		rest := strings.Join(append(append([]string(nil), words[:i]...), words[i+1:]...), " ")
+		return BangResolution{Bang: bang, Query: rest, URL: bang.Expand(rest)}, true
+	}
+	return BangResolution{}, false
+}
+
+/**
+* Expand returns the URL of the bang for query, or the home page of its site
+* when query is empty.
+**/
+func (b Bang) Expand(query string) string {
+	if query == "" && b.Domain != "" {
+		return "https://" + b.Domain
+	}
+	// Encode like JavaScript's encodeURIComponent, which DuckDuckGo uses.
+	escaped := strings.ReplaceAll(url.QueryEscape(query), "+", "%20")
+	return strings.ReplaceAll(b.URL, "{{{s}}}", escaped)
+}
@@ -0,0 +1,120 @@
+package duckduckgo
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBangsSnapshot(t *testing.T) {
+	catalog, err := ParseBangs(bangsSnapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bangs := catalog.All()
+	if len(bangs) == 0 {
+		t.Fatal("the embedded snapshot holds no bangs")
+	}
+	for _, bang := range bangs {
+		if bang.Trigger == "" || bang.Domain == "" || !strings.Contains(bang.URL, "{{{s}}}") {
+			t.Errorf("incomplete bang %+v", bang)
+		}
+	}
+	if got := len(DefaultBangs().All()); got != len(bangs) {
+		t.Errorf("DefaultBangs() holds %d bangs, want %d", got, len(bangs))
+	}
+}
+
+func testBangs() *BangCatalog {
+	return NewBangCatalog([]Bang{
+		{Trigger: "w", Name: "Wikipedia", Domain: "en.wikipedia.org", Category: "Research", Subcategory: "Reference", URL: "https://en.wikipedia.org/wiki/Special:Search?search={{{s}}}", Rank: 10},
+		{Trigger: "gh", Name: "GitHub", Domain: "github.com", Category: "Tech", Subcategory: "Programming", URL: "https://github.com/search?q={{{s}}}", Rank: 5},
+		{Trigger: "ghi", Name: "GitHub Issues", Domain: "www.github.com", Category: "Tech", Subcategory: "Programming", URL: "https://github.com/issues?q={{{s}}}", Rank: 8},
+		{Trigger: "GH", Name: "Duplicate", Domain: "example.com", URL: "https://example.com/{{{s}}}"},
+	})
+}
+
+func TestBangCatalogLookup(t *testing.T) {
+	catalog := testBangs()
+	for _, trigger := range []string{"gh", "!gh", "GH", "!Gh"} {
+		bang, ok := catalog.Lookup(trigger)
+		if !ok || bang.Name != "GitHub" {
+			t.Errorf("Lookup(%q) = %+v, %v; want GitHub, the first bang with the trigger", trigger, bang, ok)
+		}
+	}
+	if _, ok := catalog.Lookup("nope"); ok {
+		t.Error(`Lookup("nope") found a bang`)
+	}
+}
+
+func TestBangCatalogFilters(t *testing.T) {
+	catalog := testBangs()
+	names := func(bangs []Bang) []string {
+		var names []string
+		for _, bang := range bangs {
+			names = append(names, bang.Name)
+		}
+		return names
+	}
+	if got := names(catalog.ByCategory("programming")); !reflect.DeepEqual(got, []string{"GitHub Issues", "GitHub"}) {
+		t.Errorf("ByCategory() = %q, want best ranked first", got)
+	}
+	if got := names(catalog.ByDomain("www.github.com")); !reflect.DeepEqual(got, []string{"GitHub Issues", "GitHub"}) {
+		t.Errorf("ByDomain() = %q, want both github.com bangs", got)
+	}
+}
+
+func TestBangCatalogResolve(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantOK    bool
+		wantBang  string
+		wantQuery string
+		wantURL   string
+	}{
+		{query: "!w golang", wantOK: true, wantBang: "w", wantQuery: "golang", wantURL: "https://en.wikipedia.org/wiki/Special:Search?search=golang"},
+		{query: "golang generics !gh", wantOK: true, wantBang: "gh", wantQuery: "golang generics", wantURL: "https://github.com/search?q=golang%20generics"},
+		{query: "c++ & go !W", wantOK: true, wantBang: "w", wantQuery: "c++ & go", wantURL: "https://en.wikipedia.org/wiki/Special:Search?search=c%2B%2B%20%26%20go"},
+		{query: "!nope !gh x", wantOK: true, wantBang: "gh", wantQuery: "!nope x", wantURL: "https://github.com/search?q=%21nope%20x"},
+		{query: "!gh", wantOK: true, wantBang: "gh", wantQuery: "", wantURL: "https://github.com"},
+		{query: "golang", wantOK: false},
+		{query: "! golang", wantOK: false},
+		{query: "hello!gh", wantOK: false},
+	}
+	catalog := testBangs()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, ok := catalog.Resolve(tt.query)
+			if ok != tt.wantOK {
+				t.Fatalf("Resolve(%q) ok = %v, want %v", tt.query, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Bang.Trigger != tt.wantBang || got.Query != tt.wantQuery || got.URL != tt.wantURL {
+				t.Errorf("Resolve(%q) = {%s %q %s}, want {%s %q %s}",
+					tt.query, got.Bang.Trigger, got.Query, got.URL, tt.wantBang, tt.wantQuery, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestFetchBangs(t *testing.T) {
+	a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bang.js" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[{"c":"Tech","d":"go.dev","r":42,"s":"Go","sc":"Programming","t":"go","u":"https://go.dev/search?q={{{s}}}"}]`))
+	}))
+	catalog, err := a.FetchBangs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Bang{Trigger: "go", Name: "Go", Domain: "go.dev", Category: "Tech", Subcategory: "Programming", URL: "https://go.dev/search?q={{{s}}}", Rank: 42}
+	if got, ok := catalog.Lookup("go"); !ok || got != want {
+		t.Errorf("Lookup(\"go\") = %+v, want %+v", got, want)
+	}
+}
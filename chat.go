@@ -0,0 +1,245 @@
+package duckduckgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+/**
+* ChatModel is a model offered by DuckDuckGo AI Chat (duck.ai).
+**/
+type ChatModel string
+
+const (
+	ChatModelGPT4oMini    ChatModel = "gpt-4o-mini"
+	ChatModelClaude3Haiku ChatModel = "claude-3-haiku-20240307"
+	ChatModelLlama3       ChatModel = "meta-llama/Meta-Llama-3.1-70B-Instruct-Turbo"
+	ChatModelMixtral      ChatModel = "mistralai/Mixtral-8x7B-Instruct-v0.1"
+)
+
+/**
+* ChatMessage is one turn of a conversation; Role is "user" or "assistant".
+**/
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+/**
+* ErrConversationLimit is returned once a conversation has grown longer than
+* DuckDuckGo allows; start a new one.
+**/
+var ErrConversationLimit = errors.New("chat conversation limit reached")
+
+/**
+* Conversation is a chat with one model. Every message is sent together with
+* the history of the conversation, so follow-ups keep their context. Messages
+* of a conversation are exchanged one at a time.
+**/
+type Conversation struct {
+	Model ChatModel
+
+	a *AsyncDDGS
+	// exchanging serialises exchanges; mu guards vqd and messages.
+	exchanging sync.Mutex
+	mu         sync.Mutex
+	vqd        string
+	messages   []ChatMessage
+}
+
+/**
+* NewConversation starts a conversation with model, ChatModelGPT4oMini if empty.
+**/
+func (a *AsyncDDGS) NewConversation(model ChatModel) *Conversation {
+	if model == "" {
+		model = ChatModelGPT4oMini
+	}
+	return &Conversation{Model: model, a: a}
+}
+
+/**
+* Chat sends a single message to model in a new conversation and returns the reply.
+**/
+func (a *AsyncDDGS) Chat(ctx context.Context, message string, model ChatModel) (string, error) {
+	return a.NewConversation(model).Send(ctx, message)
+}
+
+/**
+* Messages returns a copy of the history of the conversation.
+**/
+func (c *Conversation) Messages() []ChatMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ChatMessage(nil), c.messages...)
+}
+
+/**
+* Send sends message and waits for the whole reply. If the reply is cut
+* short, the part received so far is returned with the error, as it is kept
+* in the history.
+**/
+func (c *Conversation) Send(ctx context.Context, message string) (string, error) {
+	it := c.Stream(ctx, message)
+	defer it.Close()
+	var reply strings.Builder
+	for it.Next() {
+		reply.WriteString(it.Result())
+	}
+	return reply.String(), it.Err()
+}
+
+/**
+* Stream sends message and yields the reply token by token as it arrives.
+* The message and the reply join the history once the reply is complete, or
+* with the part of the reply received so far if the iterator is closed or
+* the stream fails midway.
+**/
+func (c *Conversation) Stream(ctx context.Context, message string) *Iterator[string] {
+	if err := validateKeywords(message); err != nil {
+		return failedIterator[string](ctx, fmt.Errorf("chat message is mandatory"))
+	}
+	return newIterator(ctx, func(ctx context.Context, emit func(token string) bool) error {
+		c.exchanging.Lock()
+		defer c.exchanging.Unlock()
+		return c.exchange(ctx, message, emit)
+	})
+}
+
+func (c *Conversation) exchange(ctx context.Context, message string, emit func(token string) bool) error {
+	c.mu.Lock()
+	vqd := c.vqd
+	messages := append(append([]ChatMessage(nil), c.messages...), ChatMessage{Role: "user", Content: message})
+	c.mu.Unlock()
+	if vqd == "" {
+		var err error
+		if vqd, err = c.a.chatVqd(ctx); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"model":    c.Model,
+		"messages": messages,
+	})
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Accept", "text/event-stream")
+	header.Set("x-vqd-4", vqd)
+	url := c.a.url("duckduckgo.com", "/duckchat/v1/chat")
+	resp, finish, err := c.a.sendStream(ctx, "POST", url, data, nil, header)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		err = checkResponse(resp, body)
+		if err == nil {
+			err = &StatusError{StatusCode: resp.StatusCode, Endpoint: url}
+		}
+		finish(err)
+		return err
+	}
+
+	var reply strings.Builder
+	err = readChatEvents(resp.Body, url, func(token string) bool {
+		reply.WriteString(token)
+		return emit(token)
+	})
+	finish(err)
+	c.mu.Lock()
+	c.vqd = resp.Header.Get("x-vqd-4")
+	if err == nil || reply.Len() > 0 {
+		c.messages = append(messages, ChatMessage{Role: "assistant", Content: reply.String()})
+	}
+	c.mu.Unlock()
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+/**
+* Open a chat session: the status endpoint hands out the first x-vqd-4 token.
+**/
+func (a *AsyncDDGS) chatVqd(ctx context.Context) (string, error) {
+	header := http.Header{}
+	header.Set("x-vqd-accept", "1")
+	resp, finish, err := a.send(ctx, "GET", a.url("duckduckgo.com", "/duckchat/v1/status"), nil, nil, header)
+	if err != nil {
+		return "", err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		err = checkResponse(resp, body)
+	}
+	finish(err)
+	if err != nil {
+		return "", err
+	}
+	vqd := resp.Header.Get("x-vqd-4")
+	if vqd == "" {
+		return "", fmt.Errorf("chat status returned no x-vqd-4 header")
+	}
+	return vqd, nil
+}
+
+/**
+* One server-sent event of /duckchat/v1/chat.
+**/
+type chatEvent struct {
+	Message string `json:"message"`
+	Action  string `json:"action"`
+	Status  int    `json:"status"`
+	Type    string `json:"type"`
+}
+
+/**
+* Pass the tokens of a chat reply to emit until the stream ends or emit
+* returns false. The stream is made of "data: {...}" lines and ends with
+* "data: [DONE]".
+**/
+func readChatEvents(body io.Reader, endpoint string, emit func(token string) bool) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		data := bytes.TrimSpace(line[len("data:"):])
+		switch string(data) {
+		case "[DONE]":
+			return nil
+		case "[LIMIT_CONVERSATION]":
+			continue
+		}
+		var event chatEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("chat event %q: %w", data, err)
+		}
+		if event.Action == "error" {
+			switch {
+			case event.Type == "ERR_CONVERSATION_LIMIT":
+				return ErrConversationLimit
+			case event.Status == http.StatusTooManyRequests:
+				return &RatelimitError{StatusCode: event.Status, Endpoint: endpoint}
+			}
+			return fmt.Errorf("chat error %s (status %d)", event.Type, event.Status)
+		}
+		if event.Message != "" && !emit(event.Message) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
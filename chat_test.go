@@ -0,0 +1,247 @@
+package duckduckgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadChatEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  string
+		want    []string
+		wantErr any
+	}{
+		{
+			name:   "tokens",
+			stream: "data: {\"message\":\"Hel\"}\n\ndata: {\"message\":\"lo\"}\n\ndata: [DONE]\n\ndata: {\"message\":\"ignored\"}\n",
+			want:   []string{"Hel", "lo"},
+		},
+		{
+			name:   "other lines and empty messages",
+			stream: ": keep-alive\nevent: message\ndata: {\"action\":\"success\"}\ndata: {\"message\":\"Hi\"}\ndata: [LIMIT_CONVERSATION]\ndata: [DONE]\n",
+			want:   []string{"Hi"},
+		},
+		{
+			name:   "end of stream",
+			stream: "data: {\"message\":\"Hi\"}\n",
+			want:   []string{"Hi"},
+		},
+		{
+			name:    "conversation limit",
+			stream:  "data: {\"action\":\"error\",\"type\":\"ERR_CONVERSATION_LIMIT\",\"status\":400}\n",
+			wantErr: &ErrConversationLimit,
+		},
+		{
+			name:    "rate limited",
+			stream:  "data: {\"message\":\"Hi\"}\ndata: {\"action\":\"error\",\"type\":\"ERR_RATELIMIT\",\"status\":429}\n",
+			want:    []string{"Hi"},
+			wantErr: new(*RatelimitError),
+		},
+		{
+			name:    "malformed event",
+			stream:  "data: {not json\n",
+			wantErr: new(*json.SyntaxError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := readChatEvents(strings.NewReader(tt.stream), "chat", func(token string) bool {
+				got = append(got, token)
+				return true
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokens = %q, want %q", got, tt.want)
+			}
+			switch target := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("got error %v, want none", err)
+				}
+			case *error:
+				if !errors.Is(err, *target) {
+					t.Errorf("got error %v, want %v", err, *target)
+				}
+			default:
+				if !errors.As(err, target) {
+					t.Errorf("got error %v, want %T", err, target)
+				}
+			}
+		})
+	}
+}
+
+func TestReadChatEventsStops(t *testing.T) {
+	stream := "data: {\"message\":\"a\"}\ndata: {\"message\":\"b\"}\ndata: [DONE]\n"
+	var got []string
+	err := readChatEvents(strings.NewReader(stream), "chat", func(token string) bool {
+		got = append(got, token)
+		return false
+	})
+	if err != nil || !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("got %q, %v; want [a] and no error", got, err)
+	}
+}
+
+/**
+* A duck.ai test server. Every reply is tokens, sent tokenDelay apart and
+* followed by end, "data: [DONE]" unless set.
+**/
+type chatServer struct {
+	tokens     []string
+	tokenDelay time.Duration
+	end        string
+
+	vqds     atomic.Int32
+	messages atomic.Value
+}
+
+func (s *chatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/duckchat/v1/status":
+		w.Header().Set("x-vqd-4", fmt.Sprintf("vqd-%d", s.vqds.Add(1)))
+	case "/duckchat/v1/chat":
+		var body struct {
+			Messages []ChatMessage `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.messages.Store(body.Messages)
+		w.Header().Set("x-vqd-4", r.Header.Get("x-vqd-4")+"+")
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range s.tokens {
+			time.Sleep(s.tokenDelay)
+			data, _ := json.Marshal(chatEvent{Message: token})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		end := s.end
+		if end == "" {
+			end = "data: [DONE]\n\n"
+		}
+		fmt.Fprint(w, end)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *chatServer) lastMessages() []ChatMessage {
+	messages, _ := s.messages.Load().([]ChatMessage)
+	return messages
+}
+
+func TestConversationHistory(t *testing.T) {
+	srv := &chatServer{tokens: []string{"Hello", " there"}}
+	a := newTestClient(t, srv)
+	conv := a.NewConversation("")
+
+	for _, message := range []string{"hi", "again"} {
+		reply, err := conv.Send(context.Background(), message)
+		if err != nil || reply != "Hello there" {
+			t.Fatalf("Send(%q) = %q, %v", message, reply, err)
+		}
+	}
+	want := []ChatMessage{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "Hello there"},
+		{Role: "user", Content: "again"},
+		{Role: "assistant", Content: "Hello there"},
+	}
+	if got := conv.Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("history = %+v, want %+v", got, want)
+	}
+	if got := srv.lastMessages(); !reflect.DeepEqual(got, want[:3]) {
+		t.Errorf("second request sent %+v, want %+v", got, want[:3])
+	}
+	if n := srv.vqds.Load(); n != 1 {
+		t.Errorf("fetched %d session tokens, want 1", n)
+	}
+}
+
+func TestConversationSlowStream(t *testing.T) {
+	srv := &chatServer{tokens: []string{"a", "b", "c", "d", "e"}, tokenDelay: 50 * time.Millisecond}
+	a := newTestClient(t, srv, WithTimeout(100*time.Millisecond))
+	reply, err := a.Chat(context.Background(), "hi", "")
+	if err != nil || reply != "abcde" {
+		t.Errorf("Chat() = %q, %v; want the whole reply", reply, err)
+	}
+}
+
+func TestConversationStreamTimeout(t *testing.T) {
+	srv := &chatServer{tokens: []string{"a", "b", "c"}, tokenDelay: 50 * time.Millisecond}
+	a := newTestClient(t, srv)
+	ctx, cancel := context.WithTimeout(context.Background(), 75*time.Millisecond)
+	defer cancel()
+	if _, err := a.Chat(ctx, "hi", ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Chat() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestConversationMessagesWhileStreaming(t *testing.T) {
+	srv := &chatServer{tokens: []string{"a", "b"}}
+	a := newTestClient(t, srv)
+	conv := a.NewConversation("")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		it := conv.Stream(context.Background(), "hi")
+		defer it.Close()
+		for it.Next() {
+			conv.Messages()
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Messages() blocked while the reply was streaming")
+	}
+	if got := len(conv.Messages()); got != 2 {
+		t.Errorf("history holds %d messages, want 2", got)
+	}
+}
+
+func TestConversationPartialReply(t *testing.T) {
+	srv := &chatServer{
+		tokens: []string{"Hel"},
+		end:    "data: {\"action\":\"error\",\"type\":\"ERR_RATELIMIT\",\"status\":429}\n\n",
+	}
+	a := newTestClient(t, srv)
+	conv := a.NewConversation("")
+	reply, err := conv.Send(context.Background(), "hi")
+	if !isRatelimit(err) {
+		t.Fatalf("Send() error = %v, want a *RatelimitError", err)
+	}
+	history := conv.Messages()
+	if reply != "Hel" || len(history) != 2 || history[1].Content != reply {
+		t.Errorf("Send() = %q with history %+v; want the partial reply in both", reply, history)
+	}
+}
+
+func TestConversationNoResponse(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/duckchat/v1/status" {
+			w.Header().Set("x-vqd-4", "vqd")
+			return
+		}
+		<-release
+	}), WithTimeout(100*time.Millisecond))
+	start := time.Now()
+	_, err := a.Chat(context.Background(), "hi", "")
+	if err == nil || !strings.Contains(err.Error(), "no response within") {
+		t.Errorf("Chat() error = %v, want the client timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Chat() took %s, want about the client timeout", elapsed)
+	}
+}
@@ -0,0 +1,180 @@
+package duckduckgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+)
+
+/**
+* TextCursor points at the next page of a text search, exactly as the server
+* asked for it. It can be kept as a string with Token and restored with
+* ParseTextCursor, e.g. to implement "load more" across processes.
+**/
+type TextCursor struct {
+	Backend Backend           `json:"b"`
+	Params  map[string]string `json:"p"`
+}
+
+/**
+* Token encodes the cursor as an opaque URL-safe string.
+**/
+func (c TextCursor) Token() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+/**
+* ParseTextCursor decodes a token returned by TextCursor.Token.
+**/
+func ParseTextCursor(token string) (TextCursor, error) {
+	var cursor TextCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid text cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("invalid text cursor: %w", err)
+	}
+	if _, ok := textBackends[cursor.Backend]; !ok {
+		return cursor, fmt.Errorf("invalid text cursor: unknown backend %q", cursor.Backend)
+	}
+	if cursor.Params["q"] == "" {
+		return cursor, fmt.Errorf("invalid text cursor: no keywords")
+	}
+	return cursor, nil
+}
+
+/**
+* TextPageResult is one page of a text search. Next is nil on the last page,
+* Spelling unless the page reports a spelling correction.
+**/
+type TextPageResult struct {
+	Results         []TextResult
+	Next            *TextCursor
+	Spelling        *Spelling
+	RelatedSearches []string
+}
+
+/**
+* TextPage fetches the first page of a text search and a cursor to the next
+* one. Unlike Text, pages are followed one at a time using the parameters the
+* server returns, and req.MaxResults is ignored. With BackendAuto or
+* BackendRace, the backends are tried one after the other and the cursor
+* stays on the backend that answered the first page.
+**/
+func (a *AsyncDDGS) TextPage(ctx context.Context, req TextRequest) (*TextPageResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	req = req.withDefaults()
+	req.MaxResults = 0
+
+	var page *TextPageResult
+	var err error
+	for _, backend := range req.backendOrder() {
+		req.Backend = backend
+		var pager paginator[TextResult]
+		pager, err = a.textPagerFor(backend)(ctx, req)
+		if err != nil {
+			page = nil
+		} else {
+			page, err = a.textCursorPage(ctx, TextCursor{Backend: backend, Params: pager.request(0).params})
+		}
+		if ctx.Err() != nil || (page != nil && !shouldFallback(page.Results, err)) {
+			break
+		}
+		a.logf("duckduckgo: text backend %s: no first page: %v", backend, err)
+	}
+	return page, err
+}
+
+/**
+* TextResume fetches the page cursor points at and a cursor to the page after it.
+**/
+func (a *AsyncDDGS) TextResume(ctx context.Context, cursor TextCursor) (*TextPageResult, error) {
+	if _, ok := textBackends[cursor.Backend]; !ok {
+		return nil, fmt.Errorf("invalid text cursor: unknown backend %q", cursor.Backend)
+	}
+	return a.textCursorPage(ctx, cursor)
+}
+
+func (a *AsyncDDGS) textCursorPage(ctx context.Context, cursor TextCursor) (*TextPageResult, error) {
+	b := textBackends[cursor.Backend]
+	body, err := a.agetURL(ctx, b.method, a.url(b.host, b.path), nil, cursor.Params)
+	if err != nil {
+		return nil, err
+	}
+	keywords := cursor.Params["q"]
+	results, err := b.parse(body, keywords)
+	if err != nil {
+		return nil, err
+	}
+	page := &TextPageResult{
+		Results:         results,
+		Spelling:        b.spelling(body),
+		RelatedSearches: b.related(body),
+	}
+	if params := b.next(body, keywords); len(params) > 0 {
+		page.Next = &TextCursor{Backend: cursor.Backend, Params: params}
+	}
+	return page, nil
+}
+
+/**
+* text(backend="api") -> query of the next page, carried by the row with an
+* "n" key, e.g. "/d.js?q=...&s=29&vqd=...".
+**/
+func nextTextAPI(body []byte, keywords string) map[string]string {
+	pageData, err := textExtractJSON(body, keywords)
+	if err != nil {
+		return nil
+	}
+	for _, row := range pageData {
+		next, _ := row["n"].(string)
+		if next == "" {
+			continue
+		}
+		u, err := url.Parse(next)
+		if err != nil {
+			return nil
+		}
+		params := make(map[string]string)
+		for key, values := range u.Query() {
+			params[key] = values[0]
+		}
+		return params
+	}
+	return nil
+}
+
+/**
+* text(backend="html"|"lite") -> hidden fields of the "Next" form.
+**/
+func nextTextForm(body []byte, _ string) map[string]string {
+	if !bytes.Contains(body, []byte("Next")) {
+		return nil
+	}
+	tree, err := htmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	form := htmlquery.FindOne(tree, "//form[.//input[@type='submit' and contains(@value,'Next')]]")
+	if form == nil {
+		return nil
+	}
+	params := make(map[string]string)
+	for _, input := range htmlquery.Find(form, ".//input[@type='hidden']") {
+		name := strings.TrimSpace(htmlquery.SelectAttr(input, "name"))
+		if name != "" {
+			params[name] = htmlquery.SelectAttr(input, "value")
+		}
+	}
+	return params
+}
@@ -0,0 +1,111 @@
+package duckduckgo
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextCursorToken(t *testing.T) {
+	cursor := TextCursor{
+		Backend: BackendHTML,
+		Params:  map[string]string{"q": "golang generics", "s": "30", "dc": "31"},
+	}
+	token := cursor.Token()
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("token %q is not URL-safe", token)
+	}
+	got, err := ParseTextCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, cursor) {
+		t.Errorf("ParseTextCursor(Token()) = %+v, want %+v", got, cursor)
+	}
+}
+
+func TestParseTextCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "empty", token: "", wantErr: "invalid text cursor"},
+		{name: "not base64", token: "!!!", wantErr: "invalid text cursor"},
+		{name: "not json", token: encode("nope"), wantErr: "invalid text cursor"},
+		{name: "unknown backend", token: encode(`{"b":"bing","p":{"q":"x"}}`), wantErr: "unknown backend"},
+		{name: "no keywords", token: encode(`{"b":"api","p":{"s":"30"}}`), wantErr: "no keywords"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTextCursor(tt.token)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTextCursor(%q) error = %v, want %q", tt.token, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextTextAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{
+			name: "next page",
+			body: `DDG.pageLayout.load('d',[{"t":"a","u":"https://a"},{"n":"/d.js?q=go&s=29&vqd=4-1"}]);DDG.duckbar.load('x');`,
+			want: map[string]string{"q": "go", "s": "29", "vqd": "4-1"},
+		},
+		{
+			name: "last page",
+			body: `DDG.pageLayout.load('d',[{"t":"a","u":"https://a"}]);DDG.duckbar.load('x');`,
+		},
+		{
+			name: "not a results page",
+			body: `<html>nothing here</html>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextTextAPI([]byte(tt.body), "go"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextTextAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTextForm(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{
+			name: "next form",
+			body: `<form action="/html/"><input type="submit" value="Previous"><input type="hidden" name="s" value="0"></form>
+				<form action="/html/"><input type="submit" value="Next"><input type="hidden" name="q" value="go">
+				<input type="hidden" name="s" value="30"><input type="hidden" name="dc" value="31"></form>`,
+			want: map[string]string{"q": "go", "s": "30", "dc": "31"},
+		},
+		{
+			name: "last page",
+			body: `<form action="/html/"><input type="submit" value="Previous"><input type="hidden" name="s" value="0"></form>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextTextForm([]byte(tt.body), "go"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextTextForm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextExtractJSONWithoutMarker(t *testing.T) {
+	if _, err := textExtractJSON([]byte("short"), "go"); err == nil {
+		t.Error("textExtractJSON() returned no error for a body without results")
+	}
+}
@@ -0,0 +1,234 @@
+package duckduckgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/**
+* StatusError is returned when an endpoint answers with an unexpected HTTP status.
+**/
+type StatusError struct {
+	StatusCode int
+	Endpoint   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.Endpoint, e.StatusCode)
+}
+
+/**
+* RatelimitError is returned when DuckDuckGo rate limits the client, either
+* with a rate-limit status code or with a bot challenge page. RetryAfter is
+* zero unless the response carried a Retry-After header.
+**/
+type RatelimitError struct {
+	StatusCode int
+	Endpoint   string
+	RetryAfter time.Duration
+}
+
+func (e *RatelimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s rate limited the client (status %d), retry after %s", e.Endpoint, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s rate limited the client (status %d)", e.Endpoint, e.StatusCode)
+}
+
+/**
+* Markers of the pages DuckDuckGo serves instead of results to suspected bots.
+* They are markup: pages quote the query back, but always escaped, so a query
+* cannot contain the double quote every marker does.
+**/
+var challengeMarkers = [][]byte{
+	[]byte(`class="anomaly-modal`),
+	[]byte(`id="challenge-form"`),
+}
+
+/**
+* Whether resp carries a bot challenge page. Challenges are HTML, so JSON and
+* other responses are never searched for the markers.
+**/
+func isChallenge(resp *http.Response, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" {
+		return false
+	}
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+* Status codes DuckDuckGo answers with when it rate limits a client.
+**/
+func isRatelimitStatus(statusCode int) bool {
+	return statusCode == http.StatusAccepted || statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests
+}
+
+/**
+* Turn a response into a *RatelimitError or *StatusError, or nil if it looks usable.
+**/
+func checkResponse(resp *http.Response, body []byte) error {
+	endpoint := resp.Request.URL.Scheme + "://" + resp.Request.URL.Host + resp.Request.URL.Path
+	if isRatelimitStatus(resp.StatusCode) || isChallenge(resp, body) {
+		return &RatelimitError{
+			StatusCode: resp.StatusCode,
+			Endpoint:   endpoint,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode >= 400 {
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			Endpoint:   endpoint,
+		}
+	}
+	return nil
+}
+
+/**
+* Parse a Retry-After header given either in seconds or as an HTTP date.
+**/
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func isRatelimit(err error) bool {
+	var ratelimitErr *RatelimitError
+	return errors.As(err, &ratelimitErr)
+}
+
+/**
+* PageError is the failure of a single results page.
+**/
+type PageError struct {
+	Offset   int
+	Endpoint string
+	Err      error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page at offset %d of %s: %v", e.Offset, e.Endpoint, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+/**
+* PartialResultsError is returned alongside the results of the pages that
+* succeeded when some pages of a search failed. errors.As sees through it to
+* the cause of every failed page, e.g. a *RatelimitError.
+**/
+type PartialResultsError struct {
+	Pages  int
+	Failed []*PageError
+}
+
+func (e *PartialResultsError) Error() string {
+	return fmt.Sprintf("%d of %d pages failed, first: %v", len(e.Failed), e.Pages, e.Failed[0])
+}
+
+func (e *PartialResultsError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, pageErr := range e.Failed {
+		errs[i] = pageErr
+	}
+	return errs
+}
+
+/**
+* TranslateError is the failure to translate a single keyword.
+**/
+type TranslateError struct {
+	Keyword  string
+	Endpoint string
+	Err      error
+}
+
+func (e *TranslateError) Error() string {
+	return fmt.Sprintf("translating %q with %s: %v", e.Keyword, e.Endpoint, e.Err)
+}
+
+func (e *TranslateError) Unwrap() error {
+	return e.Err
+}
+
+/**
+* PartialTranslationError is returned alongside the translations that
+* succeeded when some keywords of a Translate call failed.
+**/
+type PartialTranslationError struct {
+	Keywords int
+	Failed   []*TranslateError
+}
+
+func (e *PartialTranslationError) Error() string {
+	return fmt.Sprintf("%d of %d keywords failed, first: %v", len(e.Failed), e.Keywords, e.Failed[0])
+}
+
+func (e *PartialTranslationError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, translateErr := range e.Failed {
+		errs[i] = translateErr
+	}
+	return errs
+}
+
+/**
+* Collects the failures of the page goroutines of one search.
+**/
+type pageErrors struct {
+	mu     sync.Mutex
+	failed []*PageError
+}
+
+func (p *pageErrors) add(offset int, endpoint string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed = append(p.failed, &PageError{Offset: offset, Endpoint: endpoint, Err: err})
+}
+
+func (p *pageErrors) err(pages int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.failed) == 0 {
+		return nil
+	}
+	failed := append([]*PageError(nil), p.failed...)
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Offset < failed[j].Offset })
+	return &PartialResultsError{Pages: pages, Failed: failed}
+}
+
+/**
+* Return results together with the page failures, or only the failures in strict mode.
+**/
+func withPageErrors[T any](results []T, pageErrs *pageErrors, pages int, strict bool) ([]T, error) {
+	err := pageErrs.err(pages)
+	if err != nil && strict {
+		return nil, err
+	}
+	return results, err
+}
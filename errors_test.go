@@ -0,0 +1,83 @@
+package duckduckgo
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCheckResponse(t *testing.T) {
+	const html, json = "text/html; charset=UTF-8", "application/json"
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		retryAfter  string
+		wantErr     any
+		wantAfter   time.Duration
+	}{
+		{name: "ok", status: http.StatusOK, body: "results"},
+		{name: "too many requests", status: http.StatusTooManyRequests, wantErr: new(*RatelimitError)},
+		{name: "accepted", status: http.StatusAccepted, wantErr: new(*RatelimitError)},
+		{name: "forbidden", status: http.StatusForbidden, wantErr: new(*RatelimitError)},
+		{name: "anomaly page", status: http.StatusOK, contentType: html, body: `<div class="anomaly-modal__title">Unfortunately, bots use DuckDuckGo too.</div>`, wantErr: new(*RatelimitError)},
+		{name: "challenge form", status: http.StatusOK, contentType: html, body: `<form id="challenge-form" action="/anomaly.js">`, wantErr: new(*RatelimitError)},
+		{name: "marker in query of html page", status: http.StatusOK, contentType: html, body: `<input name="q" value="css class=&quot;anomaly-modal example"><title>css anomaly-modal challenge-form</title>`},
+		{name: "marker sentence in snippet", status: http.StatusOK, contentType: html, body: `<a class="result__snippet">If this error persists, please let us know. Unfortunately, bots use DuckDuckGo too</a>`},
+		{name: "marker in query of json", status: http.StatusOK, contentType: json, body: `{"query":"css id=\"challenge-form\"","results":[]}`},
+		{name: "markup in json", status: http.StatusOK, contentType: json, body: `{"results":[{"title":"<form id=\"challenge-form\">","body":"<div class=\"anomaly-modal\">"}]}`},
+		{name: "markup in javascript", status: http.StatusOK, contentType: "application/x-javascript", body: `DDG.pageLayout.load('d',[{"a":"<div class=\"anomaly-modal\">"}]);`},
+		{name: "retry after seconds", status: http.StatusTooManyRequests, retryAfter: "30", wantErr: new(*RatelimitError), wantAfter: 30 * time.Second},
+		{name: "invalid retry after", status: http.StatusTooManyRequests, retryAfter: "soon", wantErr: new(*RatelimitError)},
+		{name: "not found", status: http.StatusNotFound, wantErr: new(*StatusError)},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: new(*StatusError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{},
+				Request:    &http.Request{URL: &url.URL{Scheme: "https", Host: "duckduckgo.com", Path: "/d.js", RawQuery: "q=x"}},
+			}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			err := checkResponse(resp, []byte(tt.body))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("got error %v, want none", err)
+				}
+				return
+			}
+			if !errors.As(err, tt.wantErr) {
+				t.Fatalf("got error %v (%T), want %T", err, err, tt.wantErr)
+			}
+			var ratelimitErr *RatelimitError
+			if errors.As(err, &ratelimitErr) {
+				if ratelimitErr.Endpoint != "https://duckduckgo.com/d.js" {
+					t.Errorf("Endpoint = %q, want the URL without query", ratelimitErr.Endpoint)
+				}
+				if ratelimitErr.RetryAfter != tt.wantAfter {
+					t.Errorf("RetryAfter = %s, want %s", ratelimitErr.RetryAfter, tt.wantAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfterDate(t *testing.T) {
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %s, want within (0, 1m]", date, got)
+	}
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(past); got != 0 {
+		t.Errorf("parseRetryAfter(%q) = %s, want 0", past, got)
+	}
+}
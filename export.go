@@ -0,0 +1,172 @@
+package duckduckgo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+/**
+* OutputFormat selects how EncodePlaces writes places.
+**/
+type OutputFormat string
+
+const (
+	OutputJSON    OutputFormat = "json"
+	OutputGeoJSON OutputFormat = "geojson"
+	OutputKML     OutputFormat = "kml"
+)
+
+/**
+* EncodePlaces writes places to w in format, e.g. the value of an output
+* format flag. An empty format writes JSON.
+**/
+func EncodePlaces(w io.Writer, places []Place, format OutputFormat) error {
+	switch format {
+	case "", OutputJSON:
+		return json.NewEncoder(w).Encode(places)
+	case OutputGeoJSON:
+		return EncodeGeoJSON(w, places)
+	case OutputKML:
+		return EncodeKML(w, places)
+	}
+	return validateEnum("output format", format, OutputJSON, OutputGeoJSON, OutputKML)
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+/**
+* EncodeGeoJSON writes places as a GeoJSON FeatureCollection of points. Every
+* field of a place but its coordinates becomes a property of its feature.
+**/
+func EncodeGeoJSON(w io.Writer, places []Place) error {
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(places)),
+	}
+	for _, place := range places {
+		properties, err := placeProperties(place)
+		if err != nil {
+			return err
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONGeometry{
+				Type:        "Point",
+				Coordinates: [2]float64{place.Coordinates.Longitude, place.Coordinates.Latitude},
+			},
+			Properties: properties,
+		})
+	}
+	return json.NewEncoder(w).Encode(collection)
+}
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Namespace  string         `xml:"xmlns,attr"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string    `xml:"name"`
+	Address     string    `xml:"address,omitempty"`
+	Description string    `xml:"description,omitempty"`
+	Data        []kmlData `xml:"ExtendedData>Data"`
+	Coordinates string    `xml:"Point>coordinates"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+/**
+* EncodeKML writes places as a KML document of placemarks. Fields without a
+* KML element of their own are kept as ExtendedData, opening hours as
+* "hours.Mon" and so on.
+**/
+func EncodeKML(w io.Writer, places []Place) error {
+	doc := kmlDocument{
+		Namespace:  "http://www.opengis.net/kml/2.2",
+		Placemarks: make([]kmlPlacemark, 0, len(places)),
+	}
+	for _, place := range places {
+		properties, err := placeProperties(place)
+		if err != nil {
+			return err
+		}
+		delete(properties, "title")
+		delete(properties, "address")
+		delete(properties, "description")
+		if hours, ok := properties["hours"].(map[string]any); ok {
+			delete(properties, "hours")
+			for day, value := range hours {
+				properties["hours."+day] = value
+			}
+		}
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		data := make([]kmlData, len(names))
+		for i, name := range names {
+			value := fmt.Sprint(properties[name])
+			if n, ok := properties[name].(float64); ok {
+				value = strconv.FormatFloat(n, 'f', -1, 64)
+			}
+			data[i] = kmlData{Name: name, Value: value}
+		}
+		doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+			Name:        place.Title,
+			Address:     place.Address,
+			Description: place.Description,
+			Data:        data,
+			Coordinates: strconv.FormatFloat(place.Coordinates.Longitude, 'f', -1, 64) + "," +
+				strconv.FormatFloat(place.Coordinates.Latitude, 'f', -1, 64),
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+/**
+* The JSON fields of place, without its coordinates.
+**/
+func placeProperties(place Place) (map[string]any, error) {
+	data, err := json.Marshal(place)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	delete(properties, "coordinates")
+	return properties, nil
+}
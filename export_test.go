@@ -0,0 +1,154 @@
+package duckduckgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var testPlaces = []Place{
+	{
+		Title:       `Café <Zürich> & "Bar"`,
+		Address:     "Bahnhofstrasse 1 & 3, 8001 Zürich",
+		CountryCode: "CH",
+		Coordinates: Coordinates{Latitude: 47.3769, Longitude: 8.5417},
+		Phone:       "+41 44 000 00 00",
+		URL:         "https://cafe.example/?a=1&b=2",
+		Source:      "Yelp",
+		Image:       "https://cafe.example/front.jpg",
+		Description: "Coffee <b>&</b> cake",
+		Hours:       map[string]string{"Mon": "08:00-18:00", "Sat": "09:00-14:00"},
+		Category:    "Cafes",
+		Rating:      4.5,
+		Reviews:     128,
+		Facebook:    "cafezurich",
+		Instagram:   "cafe.zurich",
+		Twitter:     "cafe_zurich",
+		Distance:    1250.5,
+	},
+	{
+		Title:       "Kiosk",
+		Coordinates: Coordinates{Latitude: -33.8688, Longitude: 151.2093},
+	},
+}
+
+/**
+* The JSON names of the fields of Place, except its coordinates.
+**/
+func placeFieldNames() []string {
+	var names []string
+	typ := reflect.TypeOf(Place{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "coordinates" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+/**
+* Compare got with the golden file name in testdata, rewriting it with -update.
+**/
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s", path, got)
+	}
+}
+
+func TestEncodeGeoJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodePlaces(&buf, testPlaces, OutputGeoJSON); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "places.geojson", buf.Bytes())
+
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatal(err)
+	}
+	if got := collection.Features[0].Geometry.Coordinates; got != [2]float64{8.5417, 47.3769} {
+		t.Errorf("coordinates = %v, want longitude first", got)
+	}
+	properties := collection.Features[0].Properties
+	for _, field := range placeFieldNames() {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("property %s missing", field)
+		}
+	}
+	if _, ok := properties["coordinates"]; ok {
+		t.Error("coordinates kept as a property")
+	}
+}
+
+func TestEncodeKML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodePlaces(&buf, testPlaces, OutputKML); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "places.kml", buf.Bytes())
+
+	kml := buf.String()
+	for _, want := range []string{
+		"<name>Café &lt;Zürich&gt; &amp; &#34;Bar&#34;</name>",
+		"<address>Bahnhofstrasse 1 &amp; 3, 8001 Zürich</address>",
+		"<coordinates>8.5417,47.3769</coordinates>",
+		`<Data name="hours.Mon">`,
+		`<Data name="reviews">` + "\n          <value>128</value>",
+	} {
+		if !strings.Contains(kml, want) {
+			t.Errorf("KML lacks %s", want)
+		}
+	}
+	placemark, _, _ := strings.Cut(kml, "</Placemark>")
+	elements := map[string]string{"title": "<name>", "address": "<address>", "description": "<description>", "hours": `<Data name="hours.`}
+	for _, field := range placeFieldNames() {
+		want, ok := elements[field]
+		if !ok {
+			want = `<Data name="` + field + `">`
+		}
+		if !strings.Contains(placemark, want) {
+			t.Errorf("field %s missing, want %s", field, want)
+		}
+	}
+}
+
+func TestEncodePlacesUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := EncodePlaces(&buf, testPlaces, "csv")
+	if err == nil || !strings.Contains(err.Error(), "csv") {
+		t.Errorf("EncodePlaces() = %v, want an error naming the format", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q for an unknown format", buf.String())
+	}
+}
+
+func TestEncodePlacesJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodePlaces(&buf, testPlaces, ""); err != nil {
+		t.Fatal(err)
+	}
+	var got []Place
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 2 || got[0].Title != testPlaces[0].Title {
+		t.Errorf("decoded %+v, %v; want the places back", got, err)
+	}
+}
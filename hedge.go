@@ -0,0 +1,64 @@
+package duckduckgo
+
+import (
+	"context"
+	"time"
+)
+
+/**
+* Run the backends of req concurrently and return the first response that
+* needs no fallback, cancelling the others. Each backend is started
+* req.HedgeDelay after the previous one, or as soon as every running backend
+* has failed.
+**/
+func (a *AsyncDDGS) raceText(ctx context.Context, req TextRequest) (*TextResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		resp *TextResponse
+		err  error
+	}
+	backends := req.backendOrder()
+	outcomes := make(chan outcome, len(backends))
+	hedge := time.NewTimer(0)
+	defer hedge.Stop()
+
+	failures := &textFailures{}
+	started, running := 0, 0
+	for started < len(backends) || running > 0 {
+		var next <-chan time.Time
+		if started < len(backends) {
+			next = hedge.C
+		}
+		select {
+		case <-next:
+			backend := backends[started]
+			started++
+			running++
+			go func() {
+				resp, err := a.textSearchResponse(ctx, req, backend)
+				outcomes <- outcome{resp: resp, err: err}
+			}()
+			hedge.Reset(req.HedgeDelay)
+		case o := <-outcomes:
+			running--
+			if !shouldFallback(o.resp.Results, o.err) {
+				return o.resp, o.err
+			}
+			failures.add(a, o.resp, o.err)
+			if running == 0 && started < len(backends) {
+				if !hedge.Stop() {
+					select {
+					case <-hedge.C:
+					default:
+					}
+				}
+				hedge.Reset(0)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return failures.result()
+}
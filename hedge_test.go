@@ -0,0 +1,103 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRaceTextHedgeDelay(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {body: apiBody("api"), delay: 5 * time.Second},
+		BackendHTML: {body: htmlBody("html")},
+	})
+	a := newTestClient(t, srv)
+	start := time.Now()
+	resp, err := a.SearchText(context.Background(), TextRequest{
+		Keywords:   "test",
+		Backend:    BackendRace,
+		Backends:   []Backend{BackendAPI, BackendHTML},
+		HedgeDelay: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Backend != BackendHTML || len(resp.Results) != 1 || resp.Results[0].Title != "html" {
+		t.Errorf("got %s results %+v, want the result of the faster html", resp.Backend, resp.Results)
+	}
+	if srv.startedAt(BackendAPI).IsZero() {
+		t.Error("api was never searched")
+	}
+	if after := srv.startedAt(BackendHTML).Sub(start); after < 100*time.Millisecond {
+		t.Errorf("html started %s after the search, want at least the hedge delay", after)
+	}
+	if !srv.wasCancelled(BackendAPI) {
+		t.Error("the request of the losing api backend was not cancelled")
+	}
+}
+
+func TestRaceTextFailureStartsNextBackend(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {status: http.StatusNotFound},
+		BackendHTML: {body: htmlBody("html")},
+	})
+	a := newTestClient(t, srv)
+	start := time.Now()
+	resp, err := a.SearchText(context.Background(), TextRequest{
+		Keywords:   "test",
+		Backend:    BackendRace,
+		Backends:   []Backend{BackendAPI, BackendHTML},
+		HedgeDelay: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, want html started as soon as api failed", elapsed)
+	}
+	if resp.Backend != BackendHTML {
+		t.Errorf("Backend = %s, want html", resp.Backend)
+	}
+}
+
+func TestRaceTextNoDelayStartsAll(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {body: apiBody("api"), delay: 5 * time.Second},
+		BackendHTML: {body: htmlBody("html"), delay: 5 * time.Second},
+		BackendLite: {body: liteBody("lite"), delay: 50 * time.Millisecond},
+	})
+	a := newTestClient(t, srv)
+	resp, err := a.SearchText(context.Background(), TextRequest{Keywords: "test", Backend: BackendRace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Backend != BackendLite {
+		t.Errorf("Backend = %s, want lite, the fastest", resp.Backend)
+	}
+	for _, backend := range []Backend{BackendAPI, BackendHTML} {
+		if !srv.wasCancelled(backend) {
+			t.Errorf("the request of the losing %s backend was not cancelled", backend)
+		}
+	}
+}
+
+func TestRaceTextContextCancelled(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {body: apiBody("api"), delay: 5 * time.Second},
+		BackendHTML: {body: htmlBody("html"), delay: 5 * time.Second},
+		BackendLite: {body: liteBody("lite"), delay: 5 * time.Second},
+	})
+	a := newTestClient(t, srv)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := a.SearchText(ctx, TextRequest{Keywords: "test", Backend: BackendRace, HedgeDelay: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context's", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, want to return once the context is done", elapsed)
+	}
+}
@@ -0,0 +1,49 @@
+package duckduckgo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+/**
+* Every DuckDuckGo host the tests point at the test server.
+**/
+var testHosts = []string{
+	"duckduckgo.com",
+	"links.duckduckgo.com",
+	"html.duckduckgo.com",
+	"lite.duckduckgo.com",
+}
+
+/**
+* Retry quickly, so failing tests do not sleep.
+**/
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:         3,
+	BaseDelay:           time.Millisecond,
+	MaxDelay:            5 * time.Millisecond,
+	RetryableStatus:     []int{500, 502, 503, 504},
+	RetryOnRatelimit:    true,
+	RetryOnNetworkError: true,
+}
+
+/**
+* A client whose DuckDuckGo hosts are all served by handler.
+**/
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *AsyncDDGS {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	baseURLs := make(map[string]string, len(testHosts))
+	for _, host := range testHosts {
+		baseURLs[host] = srv.URL
+	}
+	opts = append([]Option{WithBaseURLs(baseURLs), WithRetryPolicy(testRetryPolicy)}, opts...)
+	a, err := New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
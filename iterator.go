@@ -0,0 +1,141 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+)
+
+/**
+* Pages fetched ahead of the consumer of an Iterator.
+**/
+const streamWindow = 2
+
+/**
+* Iterator yields results one at a time as they arrive:
+*
+*	it := ddgs.TextIter(ctx, req)
+*	defer it.Close()
+*	for it.Next() {
+*		use(it.Result())
+*	}
+*	if err := it.Err(); err != nil { ... }
+*
+* Close stops all outstanding requests; it is safe to call more than once.
+**/
+type Iterator[T any] struct {
+	results chan T
+	done    chan struct{}
+	cancel  context.CancelFunc
+	closed  bool
+	result  T
+	err     error
+}
+
+/**
+* Run produce in the background; every result it emits is handed to Next.
+* emit returns false once the iterator is closed.
+**/
+func newIterator[T any](ctx context.Context, produce func(ctx context.Context, emit func(result T) bool) error) *Iterator[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator[T]{
+		results: make(chan T),
+		done:    make(chan struct{}),
+		cancel:  cancel,
+	}
+	go func() {
+		defer close(it.done)
+		defer close(it.results)
+		it.err = produce(ctx, func(result T) bool {
+			select {
+			case it.results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return it
+}
+
+/**
+* Next waits for the next result and reports whether there is one.
+**/
+func (it *Iterator[T]) Next() bool {
+	if it.closed {
+		return false
+	}
+	result, ok := <-it.results
+	if !ok {
+		<-it.done
+		return false
+	}
+	it.result = result
+	return true
+}
+
+/**
+* Result returns the result read by the last call to Next.
+**/
+func (it *Iterator[T]) Result() T {
+	return it.result
+}
+
+/**
+* Err returns the error that ended the iteration, if any. It is only
+* meaningful once Next has returned false.
+**/
+func (it *Iterator[T]) Err() error {
+	select {
+	case <-it.done:
+	default:
+		return nil
+	}
+	if it.closed && errors.Is(it.err, context.Canceled) {
+		return nil
+	}
+	return it.err
+}
+
+/**
+* Close stops fetching and releases the iterator.
+**/
+func (it *Iterator[T]) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.cancel()
+	<-it.done
+}
+
+/**
+* Stream the results of the pager built by build, stopping after maxResults
+* results when maxResults is positive.
+**/
+func iteratePages[T any](ctx context.Context, a *AsyncDDGS, maxResults int, build func(ctx context.Context) (paginator[T], error)) *Iterator[T] {
+	return newIterator(ctx, func(ctx context.Context, emit func(result T) bool) error {
+		pager, err := build(ctx)
+		if err != nil {
+			return err
+		}
+		pager.window = streamWindow
+		count := 0
+		pageErrs := pager.run(ctx, a, func(result T) bool {
+			if !emit(result) {
+				return false
+			}
+			count++
+			return maxResults <= 0 || count < maxResults
+		})
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return pageErrs.err(len(pager.offsets))
+	})
+}
+
+func failedIterator[T any](ctx context.Context, err error) *Iterator[T] {
+	return newIterator(ctx, func(context.Context, func(T) bool) error {
+		return err
+	})
+}
@@ -0,0 +1,118 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIteratorYieldsUntilDone(t *testing.T) {
+	errBoom := errors.New("boom")
+	it := newIterator(context.Background(), func(ctx context.Context, emit func(n int) bool) error {
+		for n := 1; n <= 3; n++ {
+			if !emit(n) {
+				return nil
+			}
+		}
+		return errBoom
+	})
+	defer it.Close()
+
+	var got []int
+	for it.Next() {
+		got = append(got, it.Result())
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("results = %v, want [1 2 3]", got)
+	}
+	if err := it.Err(); !errors.Is(err, errBoom) {
+		t.Errorf("Err() = %v, want %v", err, errBoom)
+	}
+}
+
+func TestIteratorClose(t *testing.T) {
+	stopped := make(chan struct{})
+	it := newIterator(context.Background(), func(ctx context.Context, emit func(n int) bool) error {
+		defer close(stopped)
+		for n := 0; emit(n); n++ {
+		}
+		return ctx.Err()
+	})
+	if !it.Next() || it.Result() != 0 {
+		t.Fatalf("first result = %d, want 0", it.Result())
+	}
+	it.Close()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("producer still running after Close")
+	}
+	if it.Next() {
+		t.Error("Next() = true after Close")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Err() = %v after Close, want nil", err)
+	}
+	it.Close()
+}
+
+func TestIteratorParentContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := newIterator(ctx, func(ctx context.Context, emit func(n int) bool) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	defer it.Close()
+	cancel()
+	if it.Next() {
+		t.Fatal("Next() = true after the context was canceled")
+	}
+	if err := it.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestIteratePagesMaxResults(t *testing.T) {
+	srv := &pageServer{pages: map[int]testPage{0: {body: "a b"}, 10: {body: "c d"}, 20: {body: "e"}}}
+	a := newTestClient(t, srv)
+	tests := []struct {
+		name       string
+		maxResults int
+		want       []string
+	}{
+		{name: "all", maxResults: 0, want: []string{"a", "b", "c", "d", "e"}},
+		{name: "limited", maxResults: 3, want: []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := iteratePages(context.Background(), a, tt.maxResults, func(ctx context.Context) (paginator[string], error) {
+				return testPaginator(a, []int{0, 10, 20}), nil
+			})
+			defer it.Close()
+			var got []string
+			for it.Next() {
+				got = append(got, it.Result())
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailedIterator(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	it := failedIterator[int](context.Background(), errInvalid)
+	defer it.Close()
+	if it.Next() {
+		t.Fatal("Next() = true on a failed iterator")
+	}
+	if err := it.Err(); !errors.Is(err, errInvalid) {
+		t.Errorf("Err() = %v, want %v", err, errInvalid)
+	}
+}
@@ -0,0 +1,142 @@
+package duckduckgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/**
+* Token bucket refilled at rate tokens per second, holding at most burst tokens.
+**/
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+/**
+* Take a token, waiting until one is available or ctx is done.
+**/
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		b.refund()
+		return err
+	}
+	return nil
+}
+
+/**
+* Give back a token taken by a request that was never sent.
+**/
+func (b *tokenBucket) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+/**
+* Client-side throttling shared by every request of an AsyncDDGS: a global
+* token bucket, one token bucket per host and a cap on requests in flight.
+* Each part is disabled while unset.
+**/
+type limiter struct {
+	global *tokenBucket
+
+	perHostRate  float64
+	perHostBurst int
+	mu           sync.Mutex
+	hosts        map[string]*tokenBucket
+
+	inFlight chan struct{}
+}
+
+func (l *limiter) hostBucket(host string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hosts == nil {
+		l.hosts = make(map[string]*tokenBucket)
+	}
+	bucket, ok := l.hosts[host]
+	if !ok {
+		bucket = newTokenBucket(l.perHostRate, l.perHostBurst)
+		l.hosts[host] = bucket
+	}
+	return bucket
+}
+
+/**
+* Wait until a request to host may be sent. The returned function releases
+* the in-flight slot and must be called once the response has been read.
+* If ctx is done first, the tokens already taken are given back.
+**/
+func (l *limiter) acquire(ctx context.Context, host string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	var taken []*tokenBucket
+	refund := func() {
+		for _, bucket := range taken {
+			bucket.refund()
+		}
+	}
+	if l.global != nil {
+		if err := l.global.wait(ctx); err != nil {
+			return nil, err
+		}
+		taken = append(taken, l.global)
+	}
+	if l.perHostRate > 0 {
+		bucket := l.hostBucket(host)
+		if err := bucket.wait(ctx); err != nil {
+			refund()
+			return nil, err
+		}
+		taken = append(taken, bucket)
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		refund()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,105 @@
+package duckduckgo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketWait(t *testing.T) {
+	bucket := newTokenBucket(20, 2)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The burst is free; the third token takes 1/20 s.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > time.Second {
+		t.Errorf("3 tokens took %s, want about 50ms", elapsed)
+	}
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	bucket := newTokenBucket(0.001, 1)
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx); err == nil {
+		t.Fatal("got no error, want the context error")
+	}
+	if tokens := bucketTokens(bucket); tokens < -0.01 || tokens > 0.01 {
+		t.Errorf("bucket holds %.3f tokens after a canceled wait, want 0", tokens)
+	}
+}
+
+func TestLimiterAcquireRefunds(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *limiter)
+	}{
+		{
+			name: "per-host wait canceled",
+			setup: func(l *limiter) {
+				l.perHostRate, l.perHostBurst = 0.001, 1
+				l.hostBucket("duckduckgo.com").wait(context.Background())
+			},
+		},
+		{
+			name: "in-flight wait canceled",
+			setup: func(l *limiter) {
+				l.inFlight = make(chan struct{}, 1)
+				l.inFlight <- struct{}{}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &limiter{global: newTokenBucket(0.001, 1)}
+			tt.setup(l)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if _, err := l.acquire(ctx, "duckduckgo.com"); err == nil {
+				t.Fatal("got no error, want the context error")
+			}
+			if tokens := bucketTokens(l.global); tokens < 0.99 {
+				t.Errorf("global bucket holds %.3f tokens after a canceled acquire, want 1", tokens)
+			}
+		})
+	}
+}
+
+func TestLimiterInFlight(t *testing.T) {
+	l := &limiter{inFlight: make(chan struct{}, 1)}
+	release, err := l.acquire(context.Background(), "duckduckgo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		release, err := l.acquire(context.Background(), "duckduckgo.com")
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second request was let through while the first was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second request still blocked after the first was released")
+	}
+}
+
+func bucketTokens(b *tokenBucket) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
@@ -0,0 +1,267 @@
+package duckduckgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+/**
+* MapsPageSize is the most places DuckDuckGo returns for one bounding box.
+* A box that returns this many is assumed to hold more and is split into four.
+**/
+const MapsPageSize = 20
+
+/**
+* How deep boxes are split unless MapsRequest.MaxDepth is set.
+**/
+const defaultMapsDepth = 3
+
+/**
+* Area searched by a maps request, in decimal degrees.
+**/
+type boundingBox struct {
+	top, left, bottom, right float64
+}
+
+/**
+* Grow the box by km kilometers on every side. The kilometers are converted
+* to degrees at center, so boxes stay square on the ground away from the
+* equator.
+**/
+func (b boundingBox) expand(center Coordinates, km float64) boundingBox {
+	if km == 0 {
+		return b
+	}
+	dLat := km / calculateDistance(center.Latitude, center.Longitude, center.Latitude+1, center.Longitude)
+	dLon := 180.0
+	if kmPerDegree := calculateDistance(center.Latitude, center.Longitude, center.Latitude, center.Longitude+1); kmPerDegree > km/180 {
+		dLon = km / kmPerDegree
+	}
+	return boundingBox{
+		top:    math.Min(b.top+dLat, 90),
+		left:   math.Max(b.left-dLon, -180),
+		bottom: math.Max(b.bottom-dLat, -90),
+		right:  math.Min(b.right+dLon, 180),
+	}
+}
+
+/**
+* The four quarters of the box.
+**/
+func (b boundingBox) split() []boundingBox {
+	lat, lon := (b.top+b.bottom)/2, (b.left+b.right)/2
+	return []boundingBox{
+		{top: b.top, left: b.left, bottom: lat, right: lon},
+		{top: b.top, left: lon, bottom: lat, right: b.right},
+		{top: lat, left: b.left, bottom: b.bottom, right: lon},
+		{top: lat, left: lon, bottom: b.bottom, right: b.right},
+	}
+}
+
+/**
+* Maps searches places. See MapsRequest for the available parameters.
+* If some boxes fail, the places of the others are returned together with
+* a *PartialResultsError, unless the client is strict.
+**/
+func (a *AsyncDDGS) Maps(req MapsRequest) ([]Place, error) {
+	return a.MapsContext(context.Background(), req)
+}
+
+// MapsContext is like Maps but aborts all in-flight requests once ctx is done.
+func (a *AsyncDDGS) MapsContext(ctx context.Context, req MapsRequest) ([]Place, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	req = req.withDefaults()
+
+	center, bbox, err := a.mapsArea(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	vqd, err := a.agetVqd(ctx, req.Keywords)
+	if err != nil {
+		return nil, err
+	}
+	places, err := a.mapsSearch(ctx, req, vqd, bbox.expand(center, req.Radius))
+	if places == nil {
+		return nil, err
+	}
+	for i := range places {
+		places[i].Distance = calculateDistance(center.Latitude, center.Longitude,
+			places[i].Coordinates.Latitude, places[i].Coordinates.Longitude)
+	}
+	if req.SortByDistance {
+		sort.SliceStable(places, func(i, j int) bool { return places[i].Distance < places[j].Distance })
+	}
+	return limitResults(places, req.MaxResults), err
+}
+
+/**
+* Search root and, while req.MaxResults is not reached, split every box that
+* returned a full page into four and search those too, down to *req.MaxDepth.
+* Boxes of the same depth are searched concurrently, and places found in
+* several boxes are kept once.
+**/
+func (a *AsyncDDGS) mapsSearch(ctx context.Context, req MapsRequest, vqd string, root boundingBox) ([]Place, error) {
+	endpoint := a.url("duckduckgo.com", "/local.js")
+	seen := &dedup{}
+	pageErrs := &pageErrors{}
+	var places []Place
+	boxes, fetched := []boundingBox{root}, 0
+	for depth := 0; len(boxes) > 0; depth++ {
+		pages := make([][]Place, len(boxes))
+		var wg sync.WaitGroup
+		for i, box := range boxes {
+			wg.Add(1)
+			go func(i int, box boundingBox) {
+				defer wg.Done()
+				var err error
+				pages[i], err = a.mapsPage(ctx, req.Keywords, vqd, box)
+				if err != nil && ctx.Err() == nil {
+					pageErrs.add(fetched+i, endpoint, err)
+				}
+			}(i, box)
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fetched += len(boxes)
+
+		var next []boundingBox
+		for i, page := range pages {
+			for _, place := range page {
+				if seen.add(place.Title + "\n" + place.Address) {
+					places = append(places, place)
+				}
+			}
+			if len(page) >= MapsPageSize && depth < *req.MaxDepth {
+				next = append(next, boxes[i].split()...)
+			}
+		}
+		if req.MaxResults == 0 || len(places) >= req.MaxResults {
+			break
+		}
+		boxes = next
+	}
+	return withPageErrors(places, pageErrs, fetched, a.strict)
+}
+
+/**
+* The centre and bounding box of the location of req. A Center is used as
+* is; anything else is looked up on nominatim.openstreetmap.org.
+**/
+func (a *AsyncDDGS) mapsArea(ctx context.Context, req MapsRequest) (Coordinates, boundingBox, error) {
+	if req.Center != nil {
+		c := *req.Center
+		return c, boundingBox{top: c.Latitude, left: c.Longitude, bottom: c.Latitude, right: c.Longitude}, nil
+	}
+
+	params := map[string]string{
+		"polygon_geojson": "0",
+		"format":          "jsonv2",
+	}
+	if req.Place != "" {
+		params["q"] = req.Place
+	}
+	for key, value := range req.address() {
+		params[key] = value
+	}
+	respContent, err := a.agetURL(ctx, "GET", a.url("nominatim.openstreetmap.org", "/search.php"), nil, params)
+	if err != nil {
+		return Coordinates{}, boundingBox{}, err
+	}
+	var found []nominatimPlace
+	if err := json.Unmarshal(respContent, &found); err != nil {
+		return Coordinates{}, boundingBox{}, err
+	}
+	if len(found) == 0 || len(found[0].BoundingBox) != 4 {
+		return Coordinates{}, boundingBox{}, fmt.Errorf("location not found")
+	}
+
+	var values [6]float64
+	for i, value := range append([]string{found[0].Lat, found[0].Lon}, found[0].BoundingBox...) {
+		if values[i], err = strconv.ParseFloat(value, 64); err != nil {
+			return Coordinates{}, boundingBox{}, fmt.Errorf("invalid location %q: %w", value, err)
+		}
+	}
+	center := Coordinates{Latitude: values[0], Longitude: values[1]}
+	return center, boundingBox{top: values[3], left: values[4], bottom: values[2], right: values[5]}, nil
+}
+
+/**
+* maps() -> places inside one bounding box.
+**/
+func (a *AsyncDDGS) mapsPage(ctx context.Context, keywords string, vqd string, bbox boundingBox) ([]Place, error) {
+	payload := map[string]string{
+		"q":           keywords,
+		"vqd":         vqd,
+		"tg":          "maps_places",
+		"rt":          "D",
+		"mkexp":       "b",
+		"wiki_info":   "1",
+		"is_requery":  "1",
+		"bbox_tl":     formatCoordinates(bbox.top, bbox.left),
+		"bbox_br":     formatCoordinates(bbox.bottom, bbox.right),
+		"strict_bbox": "1",
+	}
+	respContent, err := a.agetURL(ctx, "GET", a.url("duckduckgo.com", "/local.js"), nil, payload)
+	if err != nil {
+		return nil, err
+	}
+	return parseMaps(respContent)
+}
+
+func parseMaps(body []byte) ([]Place, error) {
+	var resp mapsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	places := make([]Place, 0, len(resp.Results))
+	for _, row := range resp.Results {
+		place := Place{
+			Title:       row.Name,
+			Address:     row.Address,
+			CountryCode: row.CountryCode,
+			Coordinates: row.Coordinates,
+			Phone:       row.Phone,
+			URL:         normalizeURL(row.Website),
+			Source:      normalizeURL(row.URL),
+			Category:    row.DDGCategory,
+			Rating:      rawNumber(row.Rating),
+			Reviews:     int(rawNumber(row.Reviews)),
+		}
+		if row.Embed != nil {
+			place.Image = row.Embed.Image
+			place.Description = row.Embed.Description
+		}
+		for day, hours := range row.Hours {
+			if hours, ok := hours.(string); ok {
+				if place.Hours == nil {
+					place.Hours = map[string]string{}
+				}
+				place.Hours[day] = hours
+			}
+		}
+		if row.FacebookID != "" {
+			place.Facebook = "https://www.facebook.com/profile.php?id=" + row.FacebookID
+		}
+		if row.InstagramID != "" {
+			place.Instagram = "https://www.instagram.com/" + row.InstagramID
+		}
+		if row.TwitterID != "" {
+			place.Twitter = "https://twitter.com/" + row.TwitterID
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+func formatCoordinates(latitude, longitude float64) string {
+	return strconv.FormatFloat(latitude, 'f', 6, 64) + "," + strconv.FormatFloat(longitude, 'f', 6, 64)
+}
@@ -0,0 +1,71 @@
+package duckduckgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMapsMaxDepth(t *testing.T) {
+	depth := func(n int) *int { return &n }
+	tests := []struct {
+		name       string
+		maxDepth   *int
+		maxResults int
+		boxes      int32
+	}{
+		{name: "first page only", maxDepth: depth(2), maxResults: 0, boxes: 1},
+		{name: "no splitting", maxDepth: depth(0), maxResults: 1000, boxes: 1},
+		{name: "one split", maxDepth: depth(1), maxResults: 1000, boxes: 1 + 4},
+		{name: "default depth", maxResults: 10000, boxes: 1 + 4 + 16 + 64},
+		{name: "enough results", maxResults: 50, boxes: 1 + 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var boxes, places atomic.Int32
+			a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/":
+					fmt.Fprint(w, `vqd="4-123"`)
+				case "/local.js":
+					boxes.Add(1)
+					// Every box is full of places never seen before.
+					rows := make([]map[string]any, MapsPageSize)
+					for i := range rows {
+						rows[i] = map[string]any{"name": fmt.Sprintf("place %d", places.Add(1))}
+					}
+					json.NewEncoder(w).Encode(map[string]any{"results": rows})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			_, err := a.MapsContext(context.Background(), MapsRequest{
+				Keywords:   "cafe",
+				Center:     &Coordinates{Latitude: 48.85, Longitude: 2.35},
+				MaxDepth:   tt.maxDepth,
+				MaxResults: tt.maxResults,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boxes.Load(); got != tt.boxes {
+				t.Errorf("searched %d boxes, want %d", got, tt.boxes)
+			}
+		})
+	}
+}
+
+func TestMapsRequestValidate(t *testing.T) {
+	negative := -1
+	req := MapsRequest{Keywords: "cafe", Place: "Paris", MaxDepth: &negative}
+	if err := req.Validate(); err == nil {
+		t.Error("Validate() accepted a negative MaxDepth")
+	}
+	req.MaxDepth = nil
+	if err := req.Validate(); err != nil {
+		t.Errorf("Validate() = %v for a nil MaxDepth", err)
+	}
+}
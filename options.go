@@ -0,0 +1,196 @@
+package duckduckgo
+
+import (
+	"net/http"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var defaultHeaders = map[string]string{
+	"Referer": "https://duckduckgo.com/",
+}
+
+/**
+* Logger receives diagnostic messages from AsyncDDGS. *log.Logger satisfies it.
+**/
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+/**
+* Option configures an AsyncDDGS built by New.
+**/
+type Option func(*AsyncDDGS)
+
+/**
+* WithHeaders adds headers sent with every request.
+**/
+func WithHeaders(headers map[string]string) Option {
+	return func(a *AsyncDDGS) {
+		for key, value := range headers {
+			a.headers.Set(key, value)
+		}
+	}
+}
+
+/**
+* WithUserAgent sets the User-Agent header sent with every request.
+**/
+func WithUserAgent(userAgent string) Option {
+	return func(a *AsyncDDGS) {
+		a.headers.Set("User-Agent", userAgent)
+	}
+}
+
+/**
+* WithHTTPClient uses client instead of a client built by New. WithTimeout
+* does not apply to it.
+**/
+func WithHTTPClient(client *http.Client) Option {
+	return func(a *AsyncDDGS) {
+		a.httpClient = client
+	}
+}
+
+/**
+* WithTimeout sets the timeout of a single HTTP request. Defaults to 10s.
+**/
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *AsyncDDGS) {
+		a.timeout = timeout
+	}
+}
+
+/**
+* WithProxy sends every request through the proxy at proxyURL, which may be an
+* http://, https:// or socks5:// URL with optional user:password credentials.
+**/
+func WithProxy(proxyURL string) Option {
+	return func(a *AsyncDDGS) {
+		a.proxies["all"] = proxyURL
+	}
+}
+
+/**
+* WithProxies sets a proxy per request scheme, keyed by "http", "https" or "all".
+**/
+func WithProxies(proxies map[string]string) Option {
+	return func(a *AsyncDDGS) {
+		for key, proxyURL := range proxies {
+			a.proxies[key] = proxyURL
+		}
+	}
+}
+
+/**
+* WithProxyPool rotates requests over the proxies of pool.
+**/
+func WithProxyPool(pool *ProxyPool) Option {
+	return func(a *AsyncDDGS) {
+		a.proxyPool = pool
+	}
+}
+
+/**
+* WithRetryPolicy replaces DefaultRetryPolicy. Use ContextWithRetryPolicy to
+* override it for a single call.
+**/
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(a *AsyncDDGS) {
+		a.retryPolicy = policy
+	}
+}
+
+/**
+* WithRateLimit throttles all requests of the client to rps requests per
+* second, allowing bursts of up to burst requests.
+**/
+func WithRateLimit(rps float64, burst int) Option {
+	return func(a *AsyncDDGS) {
+		if rps > 0 {
+			a.limiter.global = newTokenBucket(rps, burst)
+		}
+	}
+}
+
+/**
+* WithPerHostRateLimit is like WithRateLimit but keeps a separate budget for
+* every host, e.g. links.duckduckgo.com and html.duckduckgo.com.
+**/
+func WithPerHostRateLimit(rps float64, burst int) Option {
+	return func(a *AsyncDDGS) {
+		a.limiter.perHostRate = rps
+		a.limiter.perHostBurst = burst
+	}
+}
+
+/**
+* WithMaxInFlight caps the number of requests the client has in flight at once.
+**/
+func WithMaxInFlight(n int) Option {
+	return func(a *AsyncDDGS) {
+		if n > 0 {
+			a.limiter.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+/**
+* WithStrict makes a search fail with a *PartialResultsError as soon as one of
+* its pages fails, instead of returning the results of the other pages.
+* Translate fails the same way with a *PartialTranslationError.
+**/
+func WithStrict(strict bool) Option {
+	return func(a *AsyncDDGS) {
+		a.strict = strict
+	}
+}
+
+/**
+* WithBaseURLs replaces the base URL of DuckDuckGo hosts, keyed by host name,
+* e.g. {"duckduckgo.com": "http://127.0.0.1:8080"}.
+**/
+func WithBaseURLs(baseURLs map[string]string) Option {
+	return func(a *AsyncDDGS) {
+		for host, baseURL := range baseURLs {
+			a.baseURLs[host] = baseURL
+		}
+	}
+}
+
+/**
+* WithLogger logs every request and every dropped page to logger.
+**/
+func WithLogger(logger Logger) Option {
+	return func(a *AsyncDDGS) {
+		a.logger = logger
+	}
+}
+
+/**
+* New returns an AsyncDDGS configured by opts.
+**/
+func New(opts ...Option) (*AsyncDDGS, error) {
+	a := newAsyncDDGS(opts...)
+	if _, err := a.getExecutor(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func newAsyncDDGS(opts ...Option) *AsyncDDGS {
+	a := &AsyncDDGS{
+		proxies:     map[string]string{},
+		timeout:     defaultTimeout,
+		headers:     http.Header{},
+		baseURLs:    map[string]string{},
+		retryPolicy: DefaultRetryPolicy,
+		limiter:     &limiter{},
+	}
+	WithHeaders(defaultHeaders)(a)
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
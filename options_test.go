@@ -0,0 +1,64 @@
+package duckduckgo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHeadersReachServer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{"Referer": "https://duckduckgo.com/"},
+		},
+		{
+			name: "custom headers",
+			opts: []Option{
+				WithHeaders(map[string]string{"Accept-Language": "de-DE", "Referer": "https://example.com/"}),
+				WithUserAgent("test-agent/1.0"),
+			},
+			want: map[string]string{
+				"Accept-Language": "de-DE",
+				"Referer":         "https://example.com/",
+				"User-Agent":      "test-agent/1.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan http.Header, 1)
+			a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received <- r.Header.Clone()
+			}), tt.opts...)
+			if _, err := a.agetURL(context.Background(), "GET", a.url("duckduckgo.com", "/"), nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			header := <-received
+			for key, value := range tt.want {
+				if got := header.Get(key); got != value {
+					t.Errorf("%s = %q, want %q", key, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestNewAsyncDDGS(t *testing.T) {
+	a := NewAsyncDDGS(map[string]string{"X-Test": "1"}, nil, 3)
+	executor, err := a.getExecutor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executor.Timeout != 3*time.Second {
+		t.Errorf("Timeout = %s, want 3s", executor.Timeout)
+	}
+	if got := a.headers.Get("X-Test"); got != "1" {
+		t.Errorf("X-Test header = %q, want 1", got)
+	}
+}
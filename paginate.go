@@ -0,0 +1,241 @@
+package duckduckgo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/**
+* One page of a paginated search. Its params are a private copy, so pages
+* fetched concurrently never share mutable state.
+**/
+type pageRequest struct {
+	offset int
+	params map[string]string
+}
+
+/**
+* paginator fetches the pages of one search concurrently and merges them in
+* rank order. It is shared by the text backends, images, videos and news.
+*
+* method, endpoint: how every page is requested.
+* params: base query parameters; the page offset is added as "s".
+* offsets: the "s" value of every page, in rank order.
+* parse: extract the results of one page.
+* key: identify a result for deduplication; results with an empty key are kept.
+* inspect: optional, called with the index and body of every page that parsed.
+* trace: optional, called with the outcome of every page not cut short by ctx.
+* window: how many pages are fetched ahead of the page being emitted; 0 fetches
+* all pages at once.
+**/
+type paginator[T any] struct {
+	method   string
+	endpoint string
+	params   map[string]string
+	offsets  []int
+	parse    func(body []byte) ([]T, error)
+	key      func(result T) string
+	inspect  func(index int, body []byte)
+	trace    func(page PageMeta)
+	window   int
+}
+
+func (p paginator[T]) request(index int) pageRequest {
+	params := make(map[string]string, len(p.params)+1)
+	for key, value := range p.params {
+		params[key] = value
+	}
+	params["s"] = strconv.Itoa(p.offsets[index])
+	return pageRequest{
+		offset: p.offsets[index],
+		params: params,
+	}
+}
+
+/**
+* Fetch every page concurrently and pass the deduplicated results to emit in
+* rank order: all results of the first page, then of the second, and so on.
+* Fetching stops as soon as emit returns false or ctx is done.
+**/
+func (p paginator[T]) run(ctx context.Context, a *AsyncDDGS, emit func(result T) bool) *pageErrors {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pageErrs := &pageErrors{}
+	type page struct {
+		results []T
+		done    chan struct{}
+	}
+	pages := make([]*page, len(p.offsets))
+	for i := range pages {
+		pages[i] = &page{done: make(chan struct{})}
+	}
+	fetch := func(index int, pg *page) {
+		defer close(pg.done)
+		req := p.request(index)
+		start := time.Now()
+		pageCtx, sent := withSentURL(ctx)
+		body, err := a.agetURL(pageCtx, p.method, p.endpoint, nil, req.params)
+		if err == nil {
+			pg.results, err = p.parse(body)
+		}
+		if err == nil && p.inspect != nil {
+			p.inspect(index, body)
+		}
+		if err != nil && ctx.Err() == nil {
+			pageErrs.add(req.offset, p.endpoint, err)
+		}
+		if p.trace != nil && ctx.Err() == nil {
+			page := PageMeta{Offset: req.offset, URL: sent.url, Latency: time.Since(start)}
+			if err != nil {
+				page.Error = err.Error()
+			}
+			p.trace(page)
+		}
+	}
+
+	seen := &dedup{}
+	launched := 0
+	for i, pg := range pages {
+		for launched < len(pages) && (p.window <= 0 || launched < i+p.window) {
+			go fetch(launched, pages[launched])
+			launched++
+		}
+		select {
+		case <-pg.done:
+		case <-ctx.Done():
+			return pageErrs
+		}
+		for _, result := range pg.results {
+			if seen.add(p.key(result)) && !emit(result) {
+				return pageErrs
+			}
+		}
+	}
+	return pageErrs
+}
+
+/**
+* Fetch every page and return all deduplicated results in rank order.
+**/
+func (p paginator[T]) collect(ctx context.Context, a *AsyncDDGS) ([]T, *pageErrors) {
+	var results []T
+	pageErrs := p.run(ctx, a, func(result T) bool {
+		results = append(results, result)
+		return true
+	})
+	return results, pageErrs
+}
+
+/**
+* The URL send last requested with a context from withSentURL.
+**/
+type sentURL struct {
+	url string
+}
+
+type sentURLKey struct{}
+
+func withSentURL(ctx context.Context) (context.Context, *sentURL) {
+	sent := &sentURL{}
+	return context.WithValue(ctx, sentURLKey{}, sent), sent
+}
+
+func reportSentURL(ctx context.Context, url string) {
+	if sent, ok := ctx.Value(sentURLKey{}).(*sentURL); ok {
+		sent.url = url
+	}
+}
+
+/**
+* Set of keys already seen by a search.
+**/
+type dedup struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+/**
+* Record key and report whether it was new. Empty keys are never duplicates.
+**/
+func (d *dedup) add(key string) bool {
+	if key == "" {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.keys == nil {
+		d.keys = make(map[string]bool)
+	}
+	if d.keys[key] {
+		return false
+	}
+	d.keys[key] = true
+	return true
+}
+
+/**
+* Fetch every page and return all deduplicated results in rank order,
+* together with a *PartialResultsError if some pages failed.
+**/
+func (p paginator[T]) search(ctx context.Context, a *AsyncDDGS) ([]T, error) {
+	results, pageErrs := p.collect(ctx, a)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return withPageErrors(results, pageErrs, len(p.offsets), a.strict)
+}
+
+/**
+* Like search, but also describe the pages that were fetched.
+**/
+func (p paginator[T]) searchResponse(ctx context.Context, a *AsyncDDGS) ([]T, ResponseMeta, error) {
+	var mu sync.Mutex
+	var pages []PageMeta
+	p.trace = func(page PageMeta) {
+		mu.Lock()
+		defer mu.Unlock()
+		pages = append(pages, page)
+	}
+	results, err := p.search(ctx, a)
+
+	mu.Lock()
+	meta := ResponseMeta{VQD: p.params["vqd"], Pages: append([]PageMeta(nil), pages...)}
+	mu.Unlock()
+	sort.Slice(meta.Pages, func(i, j int) bool { return meta.Pages[i].Offset < meta.Pages[j].Offset })
+	for _, page := range meta.Pages {
+		if page.Error == "" {
+			meta.PagesFetched++
+		} else {
+			meta.PagesFailed++
+		}
+	}
+	return results, meta, err
+}
+
+/**
+* Build a pager and search it, keeping at most maxResults results. It is the
+* envelope counterpart of iteratePages.
+**/
+func searchPages[T any](ctx context.Context, a *AsyncDDGS, maxResults int, build func(ctx context.Context) (paginator[T], error)) ([]T, ResponseMeta, error) {
+	pager, err := build(ctx)
+	if err != nil {
+		return nil, ResponseMeta{}, err
+	}
+	results, meta, err := pager.searchResponse(ctx, a)
+	return limitResults(results, maxResults), meta, err
+}
+
+/**
+* Apply the MaxResults of a request: 0 keeps the first page, which is all the
+* pager fetched, and N keeps at most N results.
+**/
+func limitResults[T any](results []T, maxResults int) []T {
+	if maxResults <= 0 || len(results) <= maxResults {
+		return results
+	}
+	return results[:maxResults]
+}
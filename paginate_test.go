@@ -0,0 +1,210 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/**
+* How the test server answers the page at one offset.
+**/
+type testPage struct {
+	body   string
+	delay  time.Duration
+	status int
+}
+
+/**
+* A test server answering /page with pages[s], recording every offset it was asked for.
+**/
+type pageServer struct {
+	pages map[int]testPage
+
+	mu      sync.Mutex
+	offsets []int
+}
+
+func (s *pageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("s"))
+	s.mu.Lock()
+	s.offsets = append(s.offsets, offset)
+	s.mu.Unlock()
+	page := s.pages[offset]
+	time.Sleep(page.delay)
+	if page.status != 0 {
+		w.WriteHeader(page.status)
+	}
+	w.Write([]byte(page.body))
+}
+
+func (s *pageServer) requested() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offsets := append([]int(nil), s.offsets...)
+	sort.Ints(offsets)
+	return offsets
+}
+
+/**
+* A paginator over /page whose results are the words of every page.
+**/
+func testPaginator(a *AsyncDDGS, offsets []int) paginator[string] {
+	return paginator[string]{
+		method:   "GET",
+		endpoint: a.url("duckduckgo.com", "/page"),
+		params:   map[string]string{"q": "test"},
+		offsets:  offsets,
+		parse: func(body []byte) ([]string, error) {
+			return strings.Fields(string(body)), nil
+		},
+		key: func(result string) string { return result },
+	}
+}
+
+func TestPaginatorSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		offsets    []int
+		pages      map[int]testPage
+		strict     bool
+		want       []string
+		wantFailed []int
+	}{
+		{
+			name:    "every page gets its own offset",
+			offsets: []int{0, 10, 20},
+			pages: map[int]testPage{
+				0:  {body: "a b"},
+				10: {body: "c d"},
+				20: {body: "e"},
+			},
+			want: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:    "duplicates are dropped",
+			offsets: []int{0, 10},
+			pages: map[int]testPage{
+				0:  {body: "a b a"},
+				10: {body: "b c"},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:    "rank order when later pages answer first",
+			offsets: []int{0, 10, 20},
+			pages: map[int]testPage{
+				0:  {body: "a", delay: 60 * time.Millisecond},
+				10: {body: "b", delay: 30 * time.Millisecond},
+				20: {body: "c"},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:    "failed page",
+			offsets: []int{0, 10, 20},
+			pages: map[int]testPage{
+				0:  {body: "a"},
+				10: {status: http.StatusNotFound},
+				20: {body: "c"},
+			},
+			want:       []string{"a", "c"},
+			wantFailed: []int{10},
+		},
+		{
+			name:    "failed page in strict mode",
+			offsets: []int{0, 10},
+			pages: map[int]testPage{
+				0:  {body: "a"},
+				10: {status: http.StatusNotFound},
+			},
+			strict:     true,
+			wantFailed: []int{10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &pageServer{pages: tt.pages}
+			a := newTestClient(t, srv, WithStrict(tt.strict))
+			results, err := testPaginator(a, tt.offsets).search(context.Background(), a)
+
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Errorf("results = %q, want %q", results, tt.want)
+			}
+			if got := srv.requested(); !reflect.DeepEqual(got, tt.offsets) {
+				t.Errorf("requested offsets %v, want %v", got, tt.offsets)
+			}
+			if tt.wantFailed == nil {
+				if err != nil {
+					t.Fatalf("got error %v, want none", err)
+				}
+				return
+			}
+			var partialErr *PartialResultsError
+			if !errors.As(err, &partialErr) {
+				t.Fatalf("got error %v, want a *PartialResultsError", err)
+			}
+			var failed []int
+			for _, pageErr := range partialErr.Failed {
+				failed = append(failed, pageErr.Offset)
+			}
+			if partialErr.Pages != len(tt.offsets) || !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("%d pages with failures at %v, want %d pages with failures at %v",
+					partialErr.Pages, failed, len(tt.offsets), tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestPaginatorRunStops(t *testing.T) {
+	srv := &pageServer{pages: map[int]testPage{0: {body: "a b"}, 10: {body: "c"}, 20: {body: "d"}}}
+	a := newTestClient(t, srv)
+	pager := testPaginator(a, []int{0, 10, 20})
+	pager.window = 1
+
+	var results []string
+	pageErrs := pager.run(context.Background(), a, func(result string) bool {
+		results = append(results, result)
+		return len(results) < 2
+	})
+	if err := pageErrs.err(len(pager.offsets)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, []string{"a", "b"}) {
+		t.Errorf("results = %q, want [a b]", results)
+	}
+	// With a window of 1, no page is fetched ahead of the one being emitted.
+	if got := srv.requested(); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("requested offsets %v, want [0]", got)
+	}
+}
+
+func TestPaginatorSearchResponse(t *testing.T) {
+	srv := &pageServer{pages: map[int]testPage{0: {body: "a"}, 10: {status: http.StatusNotFound}}}
+	a := newTestClient(t, srv)
+	pager := testPaginator(a, []int{0, 10})
+	pager.params["vqd"] = "4-123"
+
+	results, meta, err := pager.searchResponse(context.Background(), a)
+	if !reflect.DeepEqual(results, []string{"a"}) || err == nil {
+		t.Fatalf("got %q, %v; want [a] and the failure of page 10", results, err)
+	}
+	if meta.VQD != "4-123" || meta.PagesFetched != 1 || meta.PagesFailed != 1 || len(meta.Pages) != 2 {
+		t.Fatalf("meta = %+v", meta)
+	}
+	for i, page := range meta.Pages {
+		if page.Offset != pager.offsets[i] || !strings.Contains(page.URL, "s="+strconv.Itoa(page.Offset)) {
+			t.Errorf("page %d = %+v, want offset %d and its URL", i, page, pager.offsets[i])
+		}
+	}
+	if meta.Pages[0].Error != "" || meta.Pages[1].Error == "" {
+		t.Errorf("page errors = %q, %q; want only the second", meta.Pages[0].Error, meta.Pages[1].Error)
+	}
+}
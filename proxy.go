@@ -0,0 +1,92 @@
+package duckduckgo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+/**
+* Build the transport proxy function from a.proxies. The keys are URL schemes
+* ("http", "https") or "all"; the values are http://, https:// or socks5://
+* proxy URLs, optionally with user:password credentials.
+**/
+func (a *AsyncDDGS) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if a.proxyPool != nil {
+		if len(a.proxies) > 0 {
+			return nil, fmt.Errorf("a proxy pool cannot be combined with WithProxy or WithProxies")
+		}
+		return a.proxyPool.proxyFunc, nil
+	}
+	if len(a.proxies) == 0 {
+		return nil, nil
+	}
+	proxies := make(map[string]*url.URL, len(a.proxies))
+	for key, rawURL := range a.proxies {
+		if key != "all" && key != "http" && key != "https" {
+			return nil, fmt.Errorf("invalid proxy key %q: want all, http or https", key)
+		}
+		proxyURL, err := parseProxyURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		proxies[key] = proxyURL
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if proxyURL, ok := proxies[req.URL.Scheme]; ok {
+			return proxyURL, nil
+		}
+		return proxies["all"], nil
+	}, nil
+}
+
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", rawURL, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https or socks5", rawURL)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", rawURL)
+	}
+	return proxyURL, nil
+}
+
+/**
+* Build the client of a, or wrap the one passed to WithHTTPClient, so that
+* every request goes through a.proxies.
+**/
+func (a *AsyncDDGS) buildExecutor() (*http.Client, error) {
+	proxy, err := a.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	if a.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxy
+		return &http.Client{
+			Transport: transport,
+			Timeout:   a.timeout,
+		}, nil
+	}
+	if proxy == nil {
+		return a.httpClient, nil
+	}
+	base := a.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("proxies require the http client to use an *http.Transport, got %T", base)
+	}
+	transport = transport.Clone()
+	transport.Proxy = proxy
+	client := *a.httpClient
+	client.Transport = transport
+	return &client, nil
+}
@@ -0,0 +1,199 @@
+package duckduckgo
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+/**
+* ProxyStrategy decides which healthy proxy of a ProxyPool serves the next request.
+**/
+type ProxyStrategy int
+
+const (
+	ProxyRoundRobin ProxyStrategy = iota
+	ProxyRandom
+	ProxyLeastRecentlyFailed
+)
+
+/**
+* ProxyPoolConfig tunes a ProxyPool. Zero values select the defaults:
+* round robin, 3 consecutive failures and a 1 minute cooldown.
+**/
+type ProxyPoolConfig struct {
+	Strategy    ProxyStrategy
+	MaxFailures int
+	Cooldown    time.Duration
+}
+
+/**
+* ProxyStats is a snapshot of the health of one proxy of a ProxyPool.
+**/
+type ProxyStats struct {
+	URL                 string    `json:"url"`
+	Healthy             bool      `json:"healthy"`
+	Requests            int       `json:"requests"`
+	Failures            int       `json:"failures"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastFailure         time.Time `json:"last_failure"`
+	UnhealthyUntil      time.Time `json:"unhealthy_until"`
+}
+
+/**
+* ProxyPool rotates requests over several proxies. A proxy is taken out of
+* rotation after MaxFailures consecutive network errors or rate-limit
+* responses, and re-admitted once Cooldown has passed.
+**/
+type ProxyPool struct {
+	config ProxyPoolConfig
+
+	mu      sync.Mutex
+	proxies []*pooledProxy
+	next    int
+	rand    *rand.Rand
+}
+
+type pooledProxy struct {
+	url                 *url.URL
+	requests            int
+	failures            int
+	consecutiveFailures int
+	lastFailure         time.Time
+	unhealthyUntil      time.Time
+}
+
+type pooledProxyKey struct{}
+
+/**
+* NewProxyPool returns a pool over proxyURLs, which accept the same URLs as WithProxy.
+**/
+func NewProxyPool(proxyURLs []string, config ProxyPoolConfig) (*ProxyPool, error) {
+	if len(proxyURLs) == 0 {
+		return nil, fmt.Errorf("proxy pool needs at least one proxy")
+	}
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = 3
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = time.Minute
+	}
+	p := &ProxyPool{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, rawURL := range proxyURLs {
+		proxyURL, err := parseProxyURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		p.proxies = append(p.proxies, &pooledProxy{url: proxyURL})
+	}
+	return p, nil
+}
+
+/**
+* Pick the proxy for the next request. When every proxy is cooling down, the
+* one that is re-admitted first is used rather than failing the request.
+**/
+func (p *ProxyPool) pick() *pooledProxy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]int, 0, len(p.proxies))
+	for i := range p.proxies {
+		index := (p.next + i) % len(p.proxies)
+		if !now.Before(p.proxies[index].unhealthyUntil) {
+			healthy = append(healthy, index)
+		}
+	}
+
+	picked := -1
+	switch {
+	case len(healthy) == 0:
+		for i, proxy := range p.proxies {
+			if picked < 0 || proxy.unhealthyUntil.Before(p.proxies[picked].unhealthyUntil) {
+				picked = i
+			}
+		}
+	case p.config.Strategy == ProxyRandom:
+		picked = healthy[p.rand.Intn(len(healthy))]
+	case p.config.Strategy == ProxyLeastRecentlyFailed:
+		for _, i := range healthy {
+			if picked < 0 || p.proxies[i].lastFailure.Before(p.proxies[picked].lastFailure) {
+				picked = i
+			}
+		}
+	default:
+		picked = healthy[0]
+	}
+	// Round robin resumes after the proxy picked, not after the one that was
+	// due, so skipping an unhealthy proxy does not favour its successor.
+	p.next = (picked + 1) % len(p.proxies)
+	proxy := p.proxies[picked]
+	proxy.requests++
+	return proxy
+}
+
+func (p *ProxyPool) markSuccess(proxy *pooledProxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	proxy.consecutiveFailures = 0
+}
+
+func (p *ProxyPool) markFailure(proxy *pooledProxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	proxy.failures++
+	proxy.consecutiveFailures++
+	proxy.lastFailure = now
+	if proxy.consecutiveFailures >= p.config.MaxFailures {
+		proxy.unhealthyUntil = now.Add(p.config.Cooldown)
+	}
+}
+
+/**
+* Stats returns a snapshot of every proxy in the pool.
+**/
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]ProxyStats, len(p.proxies))
+	for i, proxy := range p.proxies {
+		stats[i] = ProxyStats{
+			URL:                 proxy.url.Redacted(),
+			Healthy:             !now.Before(proxy.unhealthyUntil),
+			Requests:            proxy.requests,
+			Failures:            proxy.failures,
+			ConsecutiveFailures: proxy.consecutiveFailures,
+			LastFailure:         proxy.lastFailure,
+			UnhealthyUntil:      proxy.unhealthyUntil,
+		}
+	}
+	return stats
+}
+
+/**
+* Transport proxy function: use the proxy picked for the request by agetURL.
+**/
+func (p *ProxyPool) proxyFunc(req *http.Request) (*url.URL, error) {
+	if proxy, ok := req.Context().Value(pooledProxyKey{}).(*pooledProxy); ok {
+		return proxy.url, nil
+	}
+	return p.pick().url, nil
+}
+
+/**
+* Pick a proxy for one request and attach it to ctx.
+**/
+func (p *ProxyPool) attach(ctx context.Context) (context.Context, *pooledProxy) {
+	proxy := p.pick()
+	return context.WithValue(ctx, pooledProxyKey{}, proxy), proxy
+}
@@ -0,0 +1,76 @@
+package duckduckgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+)
+
+/**
+* text(backend="api") -> related searches, loaded by a call like
+* DDG.duckbar.loadModule('related_searches', {"results": [{"text": ...}]}).
+**/
+func relatedTextAPI(body []byte) []string {
+	marker := []byte("loadModule('related_searches',")
+	start := bytes.Index(body, marker)
+	if start < 0 {
+		return nil
+	}
+	var module struct {
+		Results []struct {
+			Text string `json:"text"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(body[start+len(marker):])).Decode(&module); err != nil {
+		return nil
+	}
+	var related []string
+	for _, result := range module.Results {
+		if text := strings.TrimSpace(normalize(result.Text)); text != "" {
+			related = append(related, text)
+		}
+	}
+	return related
+}
+
+/**
+* text(backend="html"|"lite") -> related searches, the links of the
+* related-searches block.
+**/
+func relatedTextHTML(body []byte) []string {
+	if !bytes.Contains(body, []byte("related-searches")) {
+		return nil
+	}
+	tree, err := htmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	var related []string
+	for _, link := range htmlquery.Find(tree, "//*[contains(@class,'related-searches')]//a") {
+		if text := strings.TrimSpace(normalize(htmlquery.InnerText(link))); text != "" {
+			related = append(related, text)
+		}
+	}
+	return related
+}
+
+/**
+* Favicon of a result, served by DuckDuckGo's icon proxy. domain defaults to
+* the host of href.
+**/
+func faviconURL(domain string, href string) string {
+	if domain == "" {
+		u, err := url.Parse(href)
+		if err != nil {
+			return ""
+		}
+		domain = u.Hostname()
+	}
+	if domain == "" || strings.ContainsAny(domain, "/?#") {
+		return ""
+	}
+	return "https://external-content.duckduckgo.com/ip3/" + domain + ".ico"
+}
@@ -0,0 +1,86 @@
+package duckduckgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelatedTextAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "related searches",
+			body: `DDG.pageLayout.load('d',[]);DDG.duckbar.load('images');` +
+				`DDG.duckbar.loadModule('related_searches', {"query":"go","results":[{"display_text":"go tutorial","text":"go tutorial"},{"text":"  "},{"text":"go &amp; rust"}]});`,
+			want: []string{"go tutorial", "go & rust"},
+		},
+		{
+			name: "missing",
+			body: `DDG.pageLayout.load('d',[]);DDG.duckbar.load('images');`,
+		},
+		{
+			name: "truncated",
+			body: `DDG.duckbar.loadModule('related_searches', {"results":[{"text":"go`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relatedTextAPI([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relatedTextAPI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelatedTextHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "html",
+			body: `<div class="result"></div><div class="results--sidebar related-searches">
+				<a href="/html/?q=go+tutorial">go <b>tutorial</b></a><a href="/html/?q=">  </a><a href="/html/?q=go+rust">go rust</a></div>`,
+			want: []string{"go tutorial", "go rust"},
+		},
+		{
+			name: "lite",
+			body: `<table class="related-searches"><tr><td><a href="/lite/?q=go+tour">go tour</a></td></tr></table>`,
+			want: []string{"go tour"},
+		},
+		{
+			name: "missing",
+			body: `<div class="result"><a href="https://go.dev/">Go</a></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relatedTextHTML([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relatedTextHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaviconURL(t *testing.T) {
+	tests := []struct {
+		domain string
+		href   string
+		want   string
+	}{
+		{domain: "go.dev", href: "https://go.dev/doc/", want: "https://external-content.duckduckgo.com/ip3/go.dev.ico"},
+		{href: "https://pkg.go.dev:443/std", want: "https://external-content.duckduckgo.com/ip3/pkg.go.dev.ico"},
+		{href: "/relative/path"},
+		{href: "://broken"},
+		{domain: "evil.example/x?y", href: "https://go.dev/"},
+	}
+	for _, tt := range tests {
+		if got := faviconURL(tt.domain, tt.href); got != tt.want {
+			t.Errorf("faviconURL(%q, %q) = %q, want %q", tt.domain, tt.href, got, tt.want)
+		}
+	}
+}
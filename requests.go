@@ -0,0 +1,610 @@
+package duckduckgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+/**
+* Region is a DuckDuckGo region code such as "wt-wt" or "us-en".
+**/
+type Region string
+
+const (
+	RegionWorldwide     Region = "wt-wt"
+	RegionUnitedStates  Region = "us-en"
+	RegionUnitedKingdom Region = "uk-en"
+	RegionGermany       Region = "de-de"
+	RegionFrance        Region = "fr-fr"
+	RegionSpain         Region = "es-es"
+	RegionJapan         Region = "jp-jp"
+	RegionChina         Region = "cn-zh"
+	RegionRussia        Region = "ru-ru"
+)
+
+var regions = []Region{
+	"xa-ar", "xa-en", "ar-es", "au-en", "at-de", "be-fr", "be-nl", "br-pt", "bg-bg", "ca-en",
+	"ca-fr", "ct-ca", "cl-es", "cn-zh", "co-es", "hr-hr", "cz-cs", "dk-da", "ee-et", "fi-fi",
+	"fr-fr", "de-de", "gr-el", "hk-tzh", "hu-hu", "in-en", "id-id", "id-en", "ie-en", "il-he",
+	"it-it", "jp-jp", "kr-kr", "lv-lv", "lt-lt", "xl-es", "my-ms", "my-en", "mx-es", "nl-nl",
+	"nz-en", "no-no", "pe-es", "ph-en", "ph-tl", "pl-pl", "pt-pt", "ro-ro", "ru-ru", "sg-en",
+	"sk-sk", "sl-sl", "za-en", "es-es", "se-sv", "ch-de", "ch-fr", "ch-it", "tw-tzh", "th-th",
+	"tr-tr", "ua-uk", "uk-en", "us-en", "ue-es", "ve-es", "vn-vi", "wt-wt",
+}
+
+/**
+* SafeSearch filters adult content. Defaults to SafeSearchModerate.
+**/
+type SafeSearch string
+
+const (
+	SafeSearchOn       SafeSearch = "on"
+	SafeSearchModerate SafeSearch = "moderate"
+	SafeSearchOff      SafeSearch = "off"
+)
+
+/**
+* The "p" query parameter shared by the html backend, images, videos and news.
+**/
+func (s SafeSearch) value() string {
+	switch s {
+	case SafeSearchOn:
+		return "1"
+	case SafeSearchOff:
+		return "-2"
+	default:
+		return "-1"
+	}
+}
+
+/**
+* TimeLimit restricts results to the last day, week, month or year.
+**/
+type TimeLimit string
+
+const (
+	TimeLimitDay   TimeLimit = "d"
+	TimeLimitWeek  TimeLimit = "w"
+	TimeLimitMonth TimeLimit = "m"
+	TimeLimitYear  TimeLimit = "y"
+)
+
+/**
+* Images spell time limits out, e.g. "Day" instead of "d".
+**/
+func (t TimeLimit) imagesValue() string {
+	switch t {
+	case TimeLimitDay:
+		return "Day"
+	case TimeLimitWeek:
+		return "Week"
+	case TimeLimitMonth:
+		return "Month"
+	case TimeLimitYear:
+		return "Year"
+	}
+	return ""
+}
+
+/**
+* Backend selects which DuckDuckGo frontend Text scrapes. BackendAuto tries
+* the backends of TextRequest.Backends in turn until one returns results;
+* BackendRace runs them concurrently and keeps the first that does.
+**/
+type Backend string
+
+const (
+	BackendAPI  Backend = "api"
+	BackendHTML Backend = "html"
+	BackendLite Backend = "lite"
+	BackendAuto Backend = "auto"
+	BackendRace Backend = "race"
+)
+
+/**
+* Order in which BackendAuto tries the backends unless TextRequest.Backends is set.
+**/
+var defaultBackendOrder = []Backend{BackendAPI, BackendHTML, BackendLite}
+
+type ImageSize string
+
+const (
+	ImageSizeSmall     ImageSize = "Small"
+	ImageSizeMedium    ImageSize = "Medium"
+	ImageSizeLarge     ImageSize = "Large"
+	ImageSizeWallpaper ImageSize = "Wallpaper"
+)
+
+type ImageColor string
+
+const (
+	ImageColorColor      ImageColor = "color"
+	ImageColorMonochrome ImageColor = "Monochrome"
+	ImageColorRed        ImageColor = "Red"
+	ImageColorOrange     ImageColor = "Orange"
+	ImageColorYellow     ImageColor = "Yellow"
+	ImageColorGreen      ImageColor = "Green"
+	ImageColorBlue       ImageColor = "Blue"
+	ImageColorPurple     ImageColor = "Purple"
+	ImageColorPink       ImageColor = "Pink"
+	ImageColorBrown      ImageColor = "Brown"
+	ImageColorBlack      ImageColor = "Black"
+	ImageColorGray       ImageColor = "Gray"
+	ImageColorTeal       ImageColor = "Teal"
+	ImageColorWhite      ImageColor = "White"
+)
+
+type ImageType string
+
+const (
+	ImageTypePhoto       ImageType = "photo"
+	ImageTypeClipart     ImageType = "clipart"
+	ImageTypeGif         ImageType = "gif"
+	ImageTypeTransparent ImageType = "transparent"
+	ImageTypeLine        ImageType = "line"
+)
+
+type ImageLayout string
+
+const (
+	ImageLayoutSquare ImageLayout = "Square"
+	ImageLayoutTall   ImageLayout = "Tall"
+	ImageLayoutWide   ImageLayout = "Wide"
+)
+
+/**
+* ImageLicense: ImageLicenseAny is any Creative Commons license.
+**/
+type ImageLicense string
+
+const (
+	ImageLicenseAny                ImageLicense = "any"
+	ImageLicensePublic             ImageLicense = "Public"
+	ImageLicenseShare              ImageLicense = "Share"
+	ImageLicenseShareCommercially  ImageLicense = "ShareCommercially"
+	ImageLicenseModify             ImageLicense = "Modify"
+	ImageLicenseModifyCommercially ImageLicense = "ModifyCommercially"
+)
+
+type VideoResolution string
+
+const (
+	VideoResolutionHigh     VideoResolution = "high"
+	VideoResolutionStandard VideoResolution = "standard"
+)
+
+type VideoDuration string
+
+const (
+	VideoDurationShort  VideoDuration = "short"
+	VideoDurationMedium VideoDuration = "medium"
+	VideoDurationLong   VideoDuration = "long"
+)
+
+type VideoLicense string
+
+const (
+	VideoLicenseCreativeCommon VideoLicense = "creativeCommon"
+	VideoLicenseYoutube        VideoLicense = "youtube"
+)
+
+/**
+* Most results each vertical can return. DuckDuckGo stops paginating there.
+**/
+const (
+	MaxTextResults   = 500
+	MaxImagesResults = 500
+	MaxVideosResults = 400
+	MaxNewsResults   = 400
+)
+
+/**
+* ErrMaxResultsExceeded is returned, wrapped, by Validate when MaxResults is
+* above the cap of its vertical.
+**/
+var ErrMaxResultsExceeded = errors.New("max results exceeded")
+
+/**
+* TextRequest describes a Text search. Zero values select the defaults:
+* worldwide region, moderate safesearch, no time limit and the api backend.
+*
+* MaxResults is the same for every vertical: 0 returns the first page only,
+* N returns at most N results. It may not exceed MaxTextResults.
+*
+* Backends is the fallback order of BackendAuto and BackendRace, api, html
+* then lite by default. It is ignored by the other backends.
+*
+* KeepSpelling searches the query as typed when DuckDuckGo would search a
+* corrected query instead, at the cost of a second search. Only the html and
+* lite backends offer a way to do so, so it cannot be combined with
+* BackendAPI. With the default backend, BackendAuto and BackendRace, a
+* search the api backend answered for a corrected query is repeated with the
+* html backend. TextIter does not apply it.
+*
+* HedgeDelay is how long BackendRace waits for a backend before also
+* starting the next one; 0 starts them all at once. Once every running
+* backend has failed, the next one starts immediately.
+**/
+type TextRequest struct {
+	Keywords     string
+	Region       Region
+	SafeSearch   SafeSearch
+	TimeLimit    TimeLimit
+	Backend      Backend
+	Backends     []Backend
+	HedgeDelay   time.Duration
+	KeepSpelling bool
+	MaxResults   int
+}
+
+func (r TextRequest) withDefaults() TextRequest {
+	r.Region = defaultRegion(r.Region)
+	r.SafeSearch = defaultSafeSearch(r.SafeSearch)
+	if r.Backend == "" {
+		r.Backend = BackendAPI
+	}
+	return r
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r TextRequest) Validate() error {
+	return firstError(
+		validateKeywords(r.Keywords),
+		validateRegion(r.Region),
+		validateEnum("safesearch", r.SafeSearch, SafeSearchOn, SafeSearchModerate, SafeSearchOff),
+		validateEnum("timelimit", r.TimeLimit, TimeLimitDay, TimeLimitWeek, TimeLimitMonth, TimeLimitYear),
+		validateEnum("backend", r.Backend, BackendAPI, BackendHTML, BackendLite, BackendAuto, BackendRace),
+		validateBackends(r.Backends),
+		validateHedgeDelay(r.HedgeDelay),
+		validateKeepSpelling(r.Backend, r.KeepSpelling),
+		validateMaxResults(r.MaxResults, MaxTextResults),
+	)
+}
+
+/**
+* The backends r is tried with, in order.
+**/
+func (r TextRequest) backendOrder() []Backend {
+	if r.Backend != BackendAuto && r.Backend != BackendRace {
+		return []Backend{r.Backend}
+	}
+	if len(r.Backends) > 0 {
+		return r.Backends
+	}
+	return defaultBackendOrder
+}
+
+func validateHedgeDelay(delay time.Duration) error {
+	if delay < 0 {
+		return fmt.Errorf("invalid hedge delay %s", delay)
+	}
+	return nil
+}
+
+func validateKeepSpelling(backend Backend, keepSpelling bool) error {
+	if keepSpelling && backend == BackendAPI {
+		return fmt.Errorf("keep spelling is not supported by the api backend")
+	}
+	return nil
+}
+
+func validateBackends(backends []Backend) error {
+	for _, backend := range backends {
+		if backend == "" {
+			return fmt.Errorf("invalid backends: empty backend")
+		}
+		if err := validateEnum("backends", backend, BackendAPI, BackendHTML, BackendLite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+* ImagesRequest describes an Images search. Zero values leave a filter unset.
+* MaxResults works as for TextRequest, up to MaxImagesResults.
+**/
+type ImagesRequest struct {
+	Keywords   string
+	Region     Region
+	SafeSearch SafeSearch
+	TimeLimit  TimeLimit
+	Size       ImageSize
+	Color      ImageColor
+	Type       ImageType
+	Layout     ImageLayout
+	License    ImageLicense
+	MaxResults int
+}
+
+func (r ImagesRequest) withDefaults() ImagesRequest {
+	r.Region = defaultRegion(r.Region)
+	r.SafeSearch = defaultSafeSearch(r.SafeSearch)
+	return r
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r ImagesRequest) Validate() error {
+	return firstError(
+		validateKeywords(r.Keywords),
+		validateRegion(r.Region),
+		validateEnum("safesearch", r.SafeSearch, SafeSearchOn, SafeSearchModerate, SafeSearchOff),
+		validateEnum("timelimit", r.TimeLimit, TimeLimitDay, TimeLimitWeek, TimeLimitMonth, TimeLimitYear),
+		validateEnum("size", r.Size, ImageSizeSmall, ImageSizeMedium, ImageSizeLarge, ImageSizeWallpaper),
+		validateEnum("color", r.Color, ImageColorColor, ImageColorMonochrome, ImageColorRed, ImageColorOrange,
+			ImageColorYellow, ImageColorGreen, ImageColorBlue, ImageColorPurple, ImageColorPink, ImageColorBrown,
+			ImageColorBlack, ImageColorGray, ImageColorTeal, ImageColorWhite),
+		validateEnum("type", r.Type, ImageTypePhoto, ImageTypeClipart, ImageTypeGif, ImageTypeTransparent, ImageTypeLine),
+		validateEnum("layout", r.Layout, ImageLayoutSquare, ImageLayoutTall, ImageLayoutWide),
+		validateEnum("license", r.License, ImageLicenseAny, ImageLicensePublic, ImageLicenseShare,
+			ImageLicenseShareCommercially, ImageLicenseModify, ImageLicenseModifyCommercially),
+		validateMaxResults(r.MaxResults, MaxImagesResults),
+	)
+}
+
+/**
+* VideosRequest describes a Videos search. Zero values leave a filter unset.
+* MaxResults works as for TextRequest, up to MaxVideosResults.
+**/
+type VideosRequest struct {
+	Keywords   string
+	Region     Region
+	SafeSearch SafeSearch
+	TimeLimit  TimeLimit
+	Resolution VideoResolution
+	Duration   VideoDuration
+	License    VideoLicense
+	MaxResults int
+}
+
+func (r VideosRequest) withDefaults() VideosRequest {
+	r.Region = defaultRegion(r.Region)
+	r.SafeSearch = defaultSafeSearch(r.SafeSearch)
+	return r
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r VideosRequest) Validate() error {
+	return firstError(
+		validateKeywords(r.Keywords),
+		validateRegion(r.Region),
+		validateEnum("safesearch", r.SafeSearch, SafeSearchOn, SafeSearchModerate, SafeSearchOff),
+		validateEnum("timelimit", r.TimeLimit, TimeLimitDay, TimeLimitWeek, TimeLimitMonth),
+		validateEnum("resolution", r.Resolution, VideoResolutionHigh, VideoResolutionStandard),
+		validateEnum("duration", r.Duration, VideoDurationShort, VideoDurationMedium, VideoDurationLong),
+		validateEnum("license", r.License, VideoLicenseCreativeCommon, VideoLicenseYoutube),
+		validateMaxResults(r.MaxResults, MaxVideosResults),
+	)
+}
+
+/**
+* NewsRequest describes a News search. MaxResults works as for TextRequest,
+* up to MaxNewsResults.
+**/
+type NewsRequest struct {
+	Keywords   string
+	Region     Region
+	SafeSearch SafeSearch
+	TimeLimit  TimeLimit
+	MaxResults int
+}
+
+func (r NewsRequest) withDefaults() NewsRequest {
+	r.Region = defaultRegion(r.Region)
+	r.SafeSearch = defaultSafeSearch(r.SafeSearch)
+	return r
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r NewsRequest) Validate() error {
+	return firstError(
+		validateKeywords(r.Keywords),
+		validateRegion(r.Region),
+		validateEnum("safesearch", r.SafeSearch, SafeSearchOn, SafeSearchModerate, SafeSearchOff),
+		validateEnum("timelimit", r.TimeLimit, TimeLimitDay, TimeLimitWeek, TimeLimitMonth),
+		validateMaxResults(r.MaxResults, MaxNewsResults),
+	)
+}
+
+/**
+* MapsRequest describes a Maps search for Keywords around a location, given
+* either as Center, as a free-form Place such as "Paris, France", or as
+* structured address fields.
+*
+* Radius widens the searched area by that many kilometers on every side; it
+* defaults to 1 around a Center. SortByDistance orders the places by their
+* distance from the centre of the search instead of by relevance.
+*
+* MaxResults 0 returns the first page only. Above that, every box that
+* returns MapsPageSize places is split into four smaller boxes, which are
+* searched in turn, until MaxResults places are found or boxes have been
+* split MaxDepth times. MaxDepth defaults to 3 when nil; 0 never splits.
+**/
+type MapsRequest struct {
+	Keywords       string
+	Place          string
+	Street         string
+	City           string
+	County         string
+	State          string
+	Country        string
+	PostalCode     string
+	Center         *Coordinates
+	Radius         float64
+	SortByDistance bool
+	MaxDepth       *int
+	MaxResults     int
+}
+
+/**
+* The structured address fields of r, keyed by their nominatim name.
+**/
+func (r MapsRequest) address() map[string]string {
+	address := map[string]string{}
+	for key, value := range map[string]string{
+		"street":     r.Street,
+		"city":       r.City,
+		"county":     r.County,
+		"state":      r.State,
+		"country":    r.Country,
+		"postalcode": r.PostalCode,
+	} {
+		if value != "" {
+			address[key] = value
+		}
+	}
+	return address
+}
+
+func (r MapsRequest) withDefaults() MapsRequest {
+	if r.Center != nil && r.Radius == 0 {
+		r.Radius = 1
+	}
+	if r.MaxDepth == nil {
+		depth := defaultMapsDepth
+		r.MaxDepth = &depth
+	}
+	return r
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r MapsRequest) Validate() error {
+	return firstError(
+		validateKeywords(r.Keywords),
+		validateLocation(r),
+		validateRadius(r.Radius),
+		validateMaxDepth(r.MaxDepth),
+		validateMaxResults(r.MaxResults, math.MaxInt),
+	)
+}
+
+func validateLocation(r MapsRequest) error {
+	address := r.address()
+	switch {
+	case r.Center != nil:
+		if r.Place != "" || len(address) > 0 {
+			return fmt.Errorf("center cannot be combined with place or address fields")
+		}
+		if math.Abs(r.Center.Latitude) > 90 || math.Abs(r.Center.Longitude) > 180 {
+			return fmt.Errorf("invalid center %v,%v", r.Center.Latitude, r.Center.Longitude)
+		}
+	case r.Place != "":
+		if len(address) > 0 {
+			return fmt.Errorf("place cannot be combined with address fields")
+		}
+	case len(address) == 0:
+		return fmt.Errorf("one of center, place or address fields is mandatory")
+	}
+	return nil
+}
+
+func validateMaxDepth(depth *int) error {
+	if depth != nil && *depth < 0 {
+		return fmt.Errorf("invalid max depth %d", *depth)
+	}
+	return nil
+}
+
+func validateRadius(radius float64) error {
+	if radius < 0 || math.IsNaN(radius) || math.IsInf(radius, 0) {
+		return fmt.Errorf("invalid radius %v", radius)
+	}
+	return nil
+}
+
+/**
+* InstantAnswerRequest describes an Instant Answer lookup. WhatIs asks
+* "what is <Keywords>" instead, which favours definitions and abstracts.
+* SkipDisambiguation drops disambiguation answers.
+**/
+type InstantAnswerRequest struct {
+	Keywords           string
+	WhatIs             bool
+	SkipDisambiguation bool
+}
+
+/**
+* Validate reports the first invalid field of r.
+**/
+func (r InstantAnswerRequest) Validate() error {
+	return validateKeywords(r.Keywords)
+}
+
+func defaultRegion(region Region) Region {
+	if region == "" {
+		return RegionWorldwide
+	}
+	return region
+}
+
+func defaultSafeSearch(safesearch SafeSearch) SafeSearch {
+	if safesearch == "" {
+		return SafeSearchModerate
+	}
+	return safesearch
+}
+
+func validateKeywords(keywords string) error {
+	if strings.TrimSpace(keywords) == "" {
+		return fmt.Errorf("keywords is mandatory")
+	}
+	return nil
+}
+
+func validateRegion(region Region) error {
+	return validateEnum("region", region, regions...)
+}
+
+/**
+* An empty value is always valid and means "use the default".
+**/
+func validateEnum[T ~string](name string, value T, allowed ...T) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range allowed {
+		if value == v {
+			return nil
+		}
+	}
+	if len(allowed) > 20 {
+		return fmt.Errorf("invalid %s %q", name, value)
+	}
+	names := make([]string, len(allowed))
+	for i, v := range allowed {
+		names[i] = string(v)
+	}
+	return fmt.Errorf("invalid %s %q: want one of %s", name, value, strings.Join(names, ", "))
+}
+
+func validateMaxResults(maxResults int, limit int) error {
+	if maxResults < 0 {
+		return fmt.Errorf("invalid max results %d", maxResults)
+	}
+	if maxResults > limit {
+		return fmt.Errorf("%w: %d is above %d", ErrMaxResultsExceeded, maxResults, limit)
+	}
+	return nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
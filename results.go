@@ -0,0 +1,428 @@
+package duckduckgo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/**
+* TextResult is a single web search result. The fields after Body are only
+* set when the backend provides them; Published and Sitelinks only come with
+* the api backend.
+**/
+type TextResult struct {
+	Title      string     `json:"title"`
+	Href       string     `json:"href"`
+	Body       string     `json:"body"`
+	DisplayURL string     `json:"display_url,omitempty"`
+	Favicon    string     `json:"favicon,omitempty"`
+	Published  *time.Time `json:"published,omitempty"`
+	Sitelinks  []Sitelink `json:"sitelinks,omitempty"`
+}
+
+/**
+* Sitelink is a deep link shown under a text result.
+**/
+type Sitelink struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+/**
+* ResponseMeta describes how a response was produced.
+*
+* VQD: the search token the pages were requested with.
+* Backend: the text backend the results came from.
+* Region: the region searched.
+* PagesFetched, PagesFailed: how many pages succeeded and failed.
+* Elapsed: the duration of the whole call, token and retries included.
+* Pages: every page requested, in rank order.
+**/
+type ResponseMeta struct {
+	VQD          string        `json:"vqd,omitempty"`
+	Backend      Backend       `json:"backend,omitempty"`
+	Region       Region        `json:"region,omitempty"`
+	PagesFetched int           `json:"pages_fetched"`
+	PagesFailed  int           `json:"pages_failed"`
+	Elapsed      time.Duration `json:"elapsed"`
+	Pages        []PageMeta    `json:"pages,omitempty"`
+}
+
+/**
+* PageMeta describes one page of a search. URL is the last URL requested for
+* it, query string and redirects included, and Latency spans every attempt.
+**/
+type PageMeta struct {
+	Offset  int           `json:"offset"`
+	URL     string        `json:"url"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+}
+
+/**
+* TextResponse is the outcome of a Text search. Spelling is nil unless
+* DuckDuckGo corrected or questioned the spelling of the query.
+* RelatedSearches are the queries DuckDuckGo suggests next to the results.
+**/
+type TextResponse struct {
+	Results         []TextResult `json:"results"`
+	Spelling        *Spelling    `json:"spelling,omitempty"`
+	RelatedSearches []string     `json:"related_searches,omitempty"`
+	ResponseMeta
+}
+
+/**
+* ImagesResponse is the outcome of an Images search.
+**/
+type ImagesResponse struct {
+	Results []ImageResult `json:"results"`
+	ResponseMeta
+}
+
+/**
+* VideosResponse is the outcome of a Videos search.
+**/
+type VideosResponse struct {
+	Results []VideoResult `json:"results"`
+	ResponseMeta
+}
+
+/**
+* NewsResponse is the outcome of a News search.
+**/
+type NewsResponse struct {
+	Results []NewsResult `json:"results"`
+	ResponseMeta
+}
+
+/**
+* Spelling is DuckDuckGo's correction of a query. Applied reports whether
+* the results are for CorrectedQuery ("Including results for ...") rather
+* than merely suggesting it ("Did you mean ...?").
+**/
+type Spelling struct {
+	CorrectedQuery string `json:"corrected_query"`
+	Applied        bool   `json:"applied"`
+
+	// Query parameters of the "Search only for" link, which searches the query as typed.
+	original map[string]string
+}
+
+/**
+* ImageResult is a single image search result.
+**/
+type ImageResult struct {
+	Title     string `json:"title"`
+	Image     string `json:"image"`
+	Thumbnail string `json:"thumbnail"`
+	URL       string `json:"url"`
+	Height    int    `json:"height"`
+	Width     int    `json:"width"`
+	Source    string `json:"source"`
+}
+
+/**
+* VideoImages holds the preview images of a video in every size DuckDuckGo offers.
+**/
+type VideoImages struct {
+	Large  string `json:"large"`
+	Medium string `json:"medium"`
+	Motion string `json:"motion"`
+	Small  string `json:"small"`
+}
+
+/**
+* VideoStatistics holds the engagement counters of a video.
+**/
+type VideoStatistics struct {
+	ViewCount int `json:"viewCount"`
+}
+
+/**
+* VideoResult is a single video search result.
+**/
+type VideoResult struct {
+	Content     string          `json:"content"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Duration    time.Duration   `json:"duration"`
+	EmbedHTML   string          `json:"embed_html"`
+	EmbedURL    string          `json:"embed_url"`
+	ImageToken  string          `json:"image_token"`
+	Images      VideoImages     `json:"images"`
+	Provider    string          `json:"provider"`
+	Published   time.Time       `json:"published"`
+	Publisher   string          `json:"publisher"`
+	Statistics  VideoStatistics `json:"statistics"`
+	Uploader    string          `json:"uploader"`
+}
+
+/**
+* NewsResult is a single news search result.
+**/
+type NewsResult struct {
+	Date   time.Time `json:"date"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body"`
+	URL    string    `json:"url"`
+	Image  string    `json:"image"`
+	Source string    `json:"source"`
+}
+
+/**
+* AnswerResult is an instant answer or one of its related topics.
+**/
+type AnswerResult struct {
+	Icon  string `json:"icon"`
+	Text  string `json:"text"`
+	Topic string `json:"topic"`
+	URL   string `json:"url"`
+}
+
+/**
+* InstantAnswerType is the kind of an instant answer.
+**/
+type InstantAnswerType string
+
+const (
+	InstantAnswerNone           InstantAnswerType = ""
+	InstantAnswerArticle        InstantAnswerType = "A"
+	InstantAnswerDisambiguation InstantAnswerType = "D"
+	InstantAnswerCategory       InstantAnswerType = "C"
+	InstantAnswerName           InstantAnswerType = "N"
+	InstantAnswerExclusive      InstantAnswerType = "E"
+)
+
+/**
+* InstantAnswer is the full response of the Instant Answer API.
+*
+* Abstract is the summary as HTML, AbstractText as plain text. Answer is set
+* by instant answers such as calculations, with AnswerType naming them.
+* RelatedTopics holds the ungrouped related topics and Groups the named ones,
+* e.g. the meanings of a disambiguation.
+**/
+type InstantAnswer struct {
+	Type             InstantAnswerType `json:"type"`
+	Heading          string            `json:"heading"`
+	Entity           string            `json:"entity"`
+	Abstract         string            `json:"abstract"`
+	AbstractText     string            `json:"abstract_text"`
+	AbstractSource   string            `json:"abstract_source"`
+	AbstractURL      string            `json:"abstract_url"`
+	Image            string            `json:"image"`
+	ImageWidth       int               `json:"image_width"`
+	ImageHeight      int               `json:"image_height"`
+	ImageIsLogo      bool              `json:"image_is_logo"`
+	Answer           string            `json:"answer"`
+	AnswerType       string            `json:"answer_type"`
+	Definition       string            `json:"definition"`
+	DefinitionSource string            `json:"definition_source"`
+	DefinitionURL    string            `json:"definition_url"`
+	Redirect         string            `json:"redirect"`
+	Infobox          *Infobox          `json:"infobox,omitempty"`
+	Results          []AnswerTopic     `json:"results"`
+	RelatedTopics    []AnswerTopic     `json:"related_topics"`
+	Groups           []AnswerGroup     `json:"groups"`
+}
+
+/**
+* AnswerTopic is a link of an instant answer. Result is the link as HTML.
+**/
+type AnswerTopic struct {
+	Text   string `json:"text"`
+	URL    string `json:"url"`
+	Icon   string `json:"icon"`
+	Result string `json:"result"`
+}
+
+/**
+* AnswerGroup is a named group of related topics.
+**/
+type AnswerGroup struct {
+	Name   string        `json:"name"`
+	Topics []AnswerTopic `json:"topics"`
+}
+
+/**
+* Infobox holds the key/value facts shown next to an instant answer.
+**/
+type Infobox struct {
+	Content []InfoboxItem `json:"content"`
+	Meta    []InfoboxItem `json:"meta"`
+}
+
+/**
+* InfoboxItem is a single fact of an Infobox. Value is the text of the fact,
+* or its JSON when it is structured.
+**/
+type InfoboxItem struct {
+	Label     string `json:"label"`
+	Value     string `json:"value"`
+	DataType  string `json:"data_type"`
+	WikiOrder int    `json:"wiki_order"`
+}
+
+/**
+* Suggestion is a single autocomplete suggestion.
+**/
+type Suggestion struct {
+	Phrase string `json:"phrase"`
+}
+
+/**
+* Coordinates is a point on Earth in decimal degrees.
+**/
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+/**
+* Place is a single maps search result. Hours maps days such as "Mon" to
+* opening hours; Distance is in kilometers from the centre of the search.
+**/
+type Place struct {
+	Title       string            `json:"title"`
+	Address     string            `json:"address"`
+	CountryCode string            `json:"country_code"`
+	Coordinates Coordinates       `json:"coordinates"`
+	Phone       string            `json:"phone"`
+	URL         string            `json:"url"`
+	Source      string            `json:"source"`
+	Image       string            `json:"image"`
+	Description string            `json:"description"`
+	Hours       map[string]string `json:"hours,omitempty"`
+	Category    string            `json:"category"`
+	Rating      float64           `json:"rating"`
+	Reviews     int               `json:"reviews"`
+	Facebook    string            `json:"facebook"`
+	Instagram   string            `json:"instagram"`
+	Twitter     string            `json:"twitter"`
+	Distance    float64           `json:"distance"`
+}
+
+// Raw rows as returned by the i.js, v.js, news.js and local.js endpoints.
+
+type imagesResponse struct {
+	Results []struct {
+		Title     string  `json:"title"`
+		Image     string  `json:"image"`
+		Thumbnail string  `json:"thumbnail"`
+		URL       string  `json:"url"`
+		Height    float64 `json:"height"`
+		Width     float64 `json:"width"`
+		Source    string  `json:"source"`
+	} `json:"results"`
+}
+
+type videosResponse struct {
+	Results []struct {
+		Content     string          `json:"content"`
+		Title       string          `json:"title"`
+		Description string          `json:"description"`
+		Duration    string          `json:"duration"`
+		EmbedHTML   string          `json:"embed_html"`
+		EmbedURL    string          `json:"embed_url"`
+		ImageToken  string          `json:"image_token"`
+		Images      VideoImages     `json:"images"`
+		Provider    string          `json:"provider"`
+		Published   string          `json:"published"`
+		Publisher   string          `json:"publisher"`
+		Statistics  VideoStatistics `json:"statistics"`
+		Uploader    string          `json:"uploader"`
+	} `json:"results"`
+}
+
+type newsResponse struct {
+	Results []struct {
+		Date    float64 `json:"date"`
+		Title   string  `json:"title"`
+		Excerpt string  `json:"excerpt"`
+		URL     string  `json:"url"`
+		Image   string  `json:"image"`
+		Source  string  `json:"source"`
+	} `json:"results"`
+}
+
+type mapsResponse struct {
+	Results []struct {
+		Name        string      `json:"name"`
+		Address     string      `json:"address"`
+		CountryCode string      `json:"country_code"`
+		Coordinates Coordinates `json:"coordinates"`
+		Phone       string      `json:"phone"`
+		Website     string      `json:"website"`
+		URL         string      `json:"url"`
+		Embed       *struct {
+			Image       string `json:"image"`
+			Description string `json:"description"`
+		} `json:"embed"`
+		Hours       map[string]any  `json:"hours"`
+		DDGCategory string          `json:"ddg_category"`
+		Rating      json.RawMessage `json:"rating"`
+		Reviews     json.RawMessage `json:"reviews"`
+		FacebookID  string          `json:"facebook_id"`
+		InstagramID string          `json:"instagram_id"`
+		TwitterID   string          `json:"twitter_id"`
+	} `json:"results"`
+}
+
+/**
+* A place found by nominatim.openstreetmap.org. Coordinates are strings and
+* boundingbox is [south, north, west, east].
+**/
+type nominatimPlace struct {
+	Lat         string   `json:"lat"`
+	Lon         string   `json:"lon"`
+	BoundingBox []string `json:"boundingbox"`
+}
+
+type answersResponse struct {
+	Type             string          `json:"Type"`
+	Heading          string          `json:"Heading"`
+	Entity           string          `json:"Entity"`
+	Abstract         string          `json:"Abstract"`
+	AbstractText     string          `json:"AbstractText"`
+	AbstractSource   string          `json:"AbstractSource"`
+	AbstractURL      string          `json:"AbstractURL"`
+	Image            string          `json:"Image"`
+	ImageWidth       json.RawMessage `json:"ImageWidth"`
+	ImageHeight      json.RawMessage `json:"ImageHeight"`
+	ImageIsLogo      json.RawMessage `json:"ImageIsLogo"`
+	Answer           json.RawMessage `json:"Answer"`
+	AnswerType       string          `json:"AnswerType"`
+	Definition       string          `json:"Definition"`
+	DefinitionSource string          `json:"DefinitionSource"`
+	DefinitionURL    string          `json:"DefinitionURL"`
+	Redirect         string          `json:"Redirect"`
+	Infobox          json.RawMessage `json:"Infobox"`
+	Results          []answersTopic  `json:"Results"`
+	RelatedTopics    []answersTopic  `json:"RelatedTopics"`
+}
+
+/**
+* The Infobox of the Instant Answer API, or "" when there is none.
+**/
+type answersInfobox struct {
+	Content []answersInfoboxItem `json:"content"`
+	Meta    []answersInfoboxItem `json:"meta"`
+}
+
+type answersInfoboxItem struct {
+	Label     string          `json:"label"`
+	Value     json.RawMessage `json:"value"`
+	DataType  string          `json:"data_type"`
+	WikiOrder json.RawMessage `json:"wiki_order"`
+}
+
+type answersTopic struct {
+	Name     string `json:"Name"`
+	Text     string `json:"Text"`
+	Result   string `json:"Result"`
+	FirstURL string `json:"FirstURL"`
+	Icon     struct {
+		URL string `json:"URL"`
+	} `json:"Icon"`
+	Topics []answersTopic `json:"Topics"`
+}
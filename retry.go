@@ -0,0 +1,119 @@
+package duckduckgo
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+/**
+* RetryPolicy controls how often a single page fetch is retried. The delay
+* before attempt n+1 is BaseDelay*2^(n-1), capped at MaxDelay, of which a
+* random fraction up to Jitter is subtracted. MaxAttempts <= 1 disables retries.
+*
+* Rate limiting (*RatelimitError) is retried when RetryOnRatelimit is set,
+* whatever its status: challenge pages come with 200, 202 or 403. Other HTTP
+* errors are retried when their status is in RetryableStatus. A Retry-After
+* sent by the server replaces the backoff, still capped at MaxDelay.
+**/
+type RetryPolicy struct {
+	MaxAttempts         int
+	BaseDelay           time.Duration
+	MaxDelay            time.Duration
+	Jitter              float64
+	RetryableStatus     []int
+	RetryOnRatelimit    bool
+	RetryOnNetworkError bool
+}
+
+/**
+* DefaultRetryPolicy is the policy of clients built by New.
+**/
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:         3,
+	BaseDelay:           500 * time.Millisecond,
+	MaxDelay:            10 * time.Second,
+	Jitter:              0.5,
+	RetryableStatus:     []int{429, 500, 502, 503, 504},
+	RetryOnRatelimit:    true,
+	RetryOnNetworkError: true,
+}
+
+type retryPolicyKey struct{}
+
+/**
+* ContextWithRetryPolicy overrides the client's retry policy for every
+* request made with the returned context.
+**/
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+func (a *AsyncDDGS) retryPolicyFor(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return a.retryPolicy
+}
+
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+* Whether a rate-limited attempt answered with statusCode may be retried.
+**/
+func (p RetryPolicy) retryableRatelimit(statusCode int) bool {
+	return p.RetryOnRatelimit || p.retryableStatus(statusCode)
+}
+
+/**
+* Delay to wait after the given (1-based) failed attempt, or retryAfter if the
+* server asked for longer. Either way it is capped at MaxDelay.
+**/
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.backoff(attempt)
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+/**
+* Delay to wait after the given (1-based) failed attempt.
+**/
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+/**
+* Sleep for d, or return ctx.Err() as soon as ctx is done.
+**/
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
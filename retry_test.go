@@ -0,0 +1,169 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{name: "first attempt", policy: policy, attempt: 1, want: 100 * time.Millisecond},
+		{name: "doubles", policy: policy, attempt: 3, want: 400 * time.Millisecond},
+		{name: "capped", policy: policy, attempt: 10, want: time.Second},
+		{name: "retry after", policy: policy, attempt: 1, retryAfter: 700 * time.Millisecond, want: 700 * time.Millisecond},
+		{name: "retry after capped", policy: policy, attempt: 1, retryAfter: time.Hour, want: time.Second},
+		{name: "backoff beats retry after", policy: policy, attempt: 4, retryAfter: 300 * time.Millisecond, want: 800 * time.Millisecond},
+		{name: "no cap", policy: RetryPolicy{BaseDelay: time.Second}, attempt: 1, retryAfter: time.Hour, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.delay(tt.attempt, tt.retryAfter); got != tt.want {
+				t.Errorf("delay(%d, %s) = %s, want %s", tt.attempt, tt.retryAfter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) = %s, want within [50ms, 100ms]", got)
+		}
+	}
+}
+
+/**
+* One canned answer of the test server.
+**/
+type cannedResponse struct {
+	status      int
+	body        string
+	contentType string
+	retryAfter  string
+}
+
+func TestAgetURLRetries(t *testing.T) {
+	ok := cannedResponse{status: http.StatusOK, body: "ok"}
+	challenge := cannedResponse{status: http.StatusOK, body: `<form id="challenge-form">`, contentType: "text/html"}
+	noRatelimitRetry := testRetryPolicy
+	noRatelimitRetry.RetryOnRatelimit = false
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		responses []cannedResponse
+		attempts  int32
+		wantErr   any
+	}{
+		{
+			name:      "server error then success",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{{status: http.StatusServiceUnavailable}, ok},
+			attempts:  2,
+		},
+		{
+			name:      "challenge page then success",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{challenge, ok},
+			attempts:  2,
+		},
+		{
+			name:      "forbidden then success",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{{status: http.StatusForbidden}, ok},
+			attempts:  2,
+		},
+		{
+			name:      "long retry after is capped",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{{status: http.StatusTooManyRequests, retryAfter: "3600"}, ok},
+			attempts:  2,
+		},
+		{
+			name:      "rate limit retries disabled",
+			policy:    noRatelimitRetry,
+			responses: []cannedResponse{challenge, ok},
+			attempts:  1,
+			wantErr:   new(*RatelimitError),
+		},
+		{
+			name:      "not retryable status",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{{status: http.StatusNotFound}, ok},
+			attempts:  1,
+			wantErr:   new(*StatusError),
+		},
+		{
+			name:      "attempts exhausted",
+			policy:    testRetryPolicy,
+			responses: []cannedResponse{{status: http.StatusBadGateway}},
+			attempts:  3,
+			wantErr:   new(*StatusError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				resp := tt.responses[len(tt.responses)-1]
+				if n < len(tt.responses) {
+					resp = tt.responses[n]
+				}
+				if resp.contentType != "" {
+					w.Header().Set("Content-Type", resp.contentType)
+				}
+				if resp.retryAfter != "" {
+					w.Header().Set("Retry-After", resp.retryAfter)
+				}
+				w.WriteHeader(resp.status)
+				w.Write([]byte(resp.body))
+			}), WithRetryPolicy(tt.policy))
+
+			start := time.Now()
+			body, err := a.agetURL(context.Background(), "GET", a.url("duckduckgo.com", "/"), nil, nil)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("took %s, want delays capped at MaxDelay", elapsed)
+			}
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("made %d attempts, want %d", got, tt.attempts)
+			}
+			if tt.wantErr == nil {
+				if err != nil || string(body) != "ok" {
+					t.Errorf("got %q, %v; want \"ok\"", body, err)
+				}
+				return
+			}
+			if !errors.As(err, tt.wantErr) {
+				t.Errorf("got error %v (%T), want %T", err, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContextWithRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	ctx := ContextWithRetryPolicy(context.Background(), RetryPolicy{MaxAttempts: 1})
+	if _, err := a.agetURL(ctx, "GET", a.url("duckduckgo.com", "/"), nil, nil); err == nil {
+		t.Fatal("got no error, want the 503")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("made %d attempts, want 1", got)
+	}
+}
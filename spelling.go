@@ -0,0 +1,82 @@
+package duckduckgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+)
+
+/**
+* text(backend="api") -> spelling correction, carried by a call like
+* DDG.page.showMessage('spelling', {"suggestionQuery": ..., "recourseQuery": ...}).
+* A recourse to the original query means the correction was applied. The
+* recourse is the query as typed, which the api backend cannot be told to keep,
+* so no parameters to search it are recorded.
+**/
+func spellingTextAPI(body []byte) *Spelling {
+	marker := []byte("showMessage('spelling',")
+	start := bytes.Index(body, marker)
+	if start < 0 {
+		return nil
+	}
+	var message struct {
+		SuggestionQuery string `json:"suggestionQuery"`
+		RecourseQuery   string `json:"recourseQuery"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(body[start+len(marker):])).Decode(&message); err != nil {
+		return nil
+	}
+	if message.SuggestionQuery == "" {
+		return nil
+	}
+	spelling := &Spelling{CorrectedQuery: message.SuggestionQuery}
+	spelling.Applied = message.RecourseQuery != ""
+	return spelling
+}
+
+/**
+* text(backend="html"|"lite") -> spelling correction from the #did_you_mean
+* message: "Including results for <a>corrected</a>. Search only for <a>typed</a>"
+* when applied, "Did you mean <a>corrected</a>?" otherwise.
+**/
+func spellingTextHTML(body []byte) *Spelling {
+	if !bytes.Contains(body, []byte("did_you_mean")) {
+		return nil
+	}
+	tree, err := htmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	message := htmlquery.FindOne(tree, "//*[@id='did_you_mean']")
+	if message == nil {
+		return nil
+	}
+	links := htmlquery.Find(message, ".//a")
+	if len(links) == 0 {
+		return nil
+	}
+	spelling := &Spelling{
+		CorrectedQuery: normalize(htmlquery.InnerText(links[0])),
+		Applied:        strings.Contains(htmlquery.InnerText(message), "Including results for"),
+	}
+	if spelling.Applied && len(links) > 1 {
+		if u, err := url.Parse(htmlquery.SelectAttr(links[1], "href")); err == nil && u.Query().Get("q") != "" {
+			spelling.original = map[string]string{}
+			for key, values := range u.Query() {
+				spelling.original[key] = values[0]
+			}
+		}
+	}
+	return spelling
+}
+
+/**
+* Whether the "Search only for" link would search anything other than the
+* request for keywords that was already made.
+**/
+func (s *Spelling) searchesOriginal(keywords string) bool {
+	return s.original["q"] != "" && (len(s.original) > 1 || s.original["q"] != keywords)
+}
@@ -0,0 +1,157 @@
+package duckduckgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSpellingTextAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *Spelling
+	}{
+		{
+			name: "applied",
+			body: `DDG.page.showMessage('spelling', {"suggestionQuery":"golang","recourseQuery":"golnag"});`,
+			want: &Spelling{CorrectedQuery: "golang", Applied: true},
+		},
+		{
+			name: "suggested",
+			body: `DDG.page.showMessage('spelling', {"suggestionQuery":"golang"});`,
+			want: &Spelling{CorrectedQuery: "golang"},
+		},
+		{
+			name: "none",
+			body: `DDG.pageLayout.load('d',[]);`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spellingTextAPI([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spellingTextAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpellingTextHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *Spelling
+	}{
+		{
+			name: "applied",
+			body: `<div id="did_you_mean">Including results for <a href="/html/?q=golang">golang</a>.
+				Search only for <a href="/html/?q=golnag&amp;norw=1">golnag</a></div>`,
+			want: &Spelling{CorrectedQuery: "golang", Applied: true, original: map[string]string{"q": "golnag", "norw": "1"}},
+		},
+		{
+			name: "suggested",
+			body: `<div id="did_you_mean">Did you mean <a href="/html/?q=golang">golang</a>?</div>`,
+			want: &Spelling{CorrectedQuery: "golang"},
+		},
+		{
+			name: "none",
+			body: `<div class="results"></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spellingTextHTML([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spellingTextHTML() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepSpelling(t *testing.T) {
+	const (
+		corrected = `<div id="did_you_mean">Including results for <a href="/html/?q=golang">golang</a>.
+			Search only for <a href="/html/?q=golnag&amp;norw=1">golnag</a></div>`
+		result = `<div><h2><a href="https://%s.example">%s</a></h2><a href="https://%s.example">snippet</a></div>`
+	)
+	tests := []struct {
+		name         string
+		backend      Backend
+		keepSpelling bool
+		recorrected  bool
+		requests     int32
+		wantBackend  Backend
+		wantTitle    string
+		wantApplied  bool
+	}{
+		{name: "corrected results", backend: BackendHTML, requests: 1, wantBackend: BackendHTML, wantTitle: "golang", wantApplied: true},
+		{name: "query as typed", backend: BackendHTML, keepSpelling: true, requests: 2, wantBackend: BackendHTML, wantTitle: "golnag"},
+		{name: "corrected again", backend: BackendHTML, keepSpelling: true, recorrected: true, requests: 2, wantBackend: BackendHTML, wantTitle: "golnag", wantApplied: true},
+		{name: "api corrected results", backend: BackendAPI, requests: 1, wantBackend: BackendAPI, wantTitle: "golang", wantApplied: true},
+		{name: "default backend repeated with html", keepSpelling: true, requests: 3, wantBackend: BackendHTML, wantTitle: "golnag"},
+		{name: "auto repeated with html", backend: BackendAuto, keepSpelling: true, requests: 3, wantBackend: BackendHTML, wantTitle: "golnag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/":
+					fmt.Fprint(w, `vqd="4-123"`)
+				case "/d.js":
+					requests.Add(1)
+					fmt.Fprint(w, `DDG.pageLayout.load('d',[{"t":"golang","u":"https://golang.example","a":"snippet"}]);`+
+						`DDG.duckbar.load('images');DDG.page.showMessage('spelling', {"suggestionQuery":"golang","recourseQuery":"golnag"});`)
+				case "/html":
+					requests.Add(1)
+					if r.URL.Query().Get("norw") == "1" {
+						if tt.recorrected {
+							fmt.Fprint(w, corrected)
+						}
+						fmt.Fprintf(w, result, "golnag", "golnag", "golnag")
+						return
+					}
+					fmt.Fprint(w, corrected)
+					fmt.Fprintf(w, result, "golang", "golang", "golang")
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			resp, err := a.SearchText(context.Background(), TextRequest{
+				Keywords:     "golnag",
+				Backend:      tt.backend,
+				KeepSpelling: tt.keepSpelling,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("made %d searches, want %d", got, tt.requests)
+			}
+			if resp.Backend != tt.wantBackend {
+				t.Errorf("Backend = %s, want %s", resp.Backend, tt.wantBackend)
+			}
+			if len(resp.Results) != 1 || resp.Results[0].Title != tt.wantTitle {
+				t.Errorf("results = %+v, want the results for %q", resp.Results, tt.wantTitle)
+			}
+			if resp.Spelling == nil || resp.Spelling.CorrectedQuery != "golang" || resp.Spelling.Applied != tt.wantApplied {
+				t.Errorf("spelling = %+v, want golang with Applied %v", resp.Spelling, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestKeepSpellingRejectedByAPI(t *testing.T) {
+	req := TextRequest{Keywords: "golnag", Backend: BackendAPI, KeepSpelling: true}
+	if err := req.Validate(); err == nil {
+		t.Error("Validate() accepted KeepSpelling with the api backend")
+	}
+	for _, backend := range []Backend{"", BackendHTML, BackendLite, BackendAuto, BackendRace} {
+		req.Backend = backend
+		if err := req.Validate(); err != nil {
+			t.Errorf("Validate() with backend %q = %v", backend, err)
+		}
+	}
+}
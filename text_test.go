@@ -0,0 +1,456 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/**
+* A d.js page with one result per title.
+**/
+func apiBody(titles ...string) string {
+	rows := make([]string, len(titles))
+	for i, title := range titles {
+		rows[i] = fmt.Sprintf(`{"t":"%s","u":"https://%s.example/","a":"snippet of %s"}`, title, title, title)
+	}
+	return `DDG.pageLayout.load('d',[` + strings.Join(rows, ",") + `]);DDG.duckbar.load('images');`
+}
+
+/**
+* An html backend page with one result per title.
+**/
+func htmlBody(titles ...string) string {
+	var b strings.Builder
+	for _, title := range titles {
+		fmt.Fprintf(&b, `<div><h2><a href="https://%s.example/">%s</a></h2><a href="https://%s.example/">snippet of %s</a></div>`, title, title, title, title)
+	}
+	return b.String()
+}
+
+/**
+* A lite backend page with one result per title, four rows each.
+**/
+func liteBody(titles ...string) string {
+	var b strings.Builder
+	b.WriteString(`<table><tr><td>header</td></tr></table><table>`)
+	for _, title := range titles {
+		fmt.Fprintf(&b, `<tr><td><a rel="nofollow" href="https://%s.example/" class="result-link">%s</a></td></tr>`, title, title)
+		fmt.Fprintf(&b, `<tr><td class="result-snippet">snippet of %s</td></tr>`, title)
+		fmt.Fprintf(&b, `<tr><td><span class="link-text">%s.example</span></td></tr>`, title)
+		b.WriteString(`<tr><td>&nbsp;</td></tr>`)
+	}
+	b.WriteString(`</table>`)
+	return b.String()
+}
+
+/**
+* How the test server answers the searches of one text backend.
+**/
+type backendAnswer struct {
+	status int
+	body   string
+	delay  time.Duration
+}
+
+var textBackendPaths = map[string]Backend{
+	"/d.js":  BackendAPI,
+	"/html":  BackendHTML,
+	"/lite/": BackendLite,
+}
+
+/**
+* A test server for the text backends. It records the order in which the
+* backends were searched, when each was first searched and whether a
+* request was cancelled before it was answered.
+**/
+type textServer struct {
+	answers map[Backend]backendAnswer
+
+	mu        sync.Mutex
+	searched  []Backend
+	started   map[Backend]time.Time
+	cancelled map[Backend]bool
+}
+
+func newTextServer(answers map[Backend]backendAnswer) *textServer {
+	return &textServer{answers: answers, started: map[Backend]time.Time{}, cancelled: map[Backend]bool{}}
+}
+
+func (s *textServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	backend, ok := textBackendPaths[r.URL.Path]
+	if !ok {
+		fmt.Fprint(w, `vqd="4-123"`)
+		return
+	}
+	s.mu.Lock()
+	if _, ok := s.started[backend]; !ok {
+		s.started[backend] = time.Now()
+		s.searched = append(s.searched, backend)
+	}
+	s.mu.Unlock()
+
+	answer := s.answers[backend]
+	select {
+	case <-time.After(answer.delay):
+	case <-r.Context().Done():
+		s.mu.Lock()
+		s.cancelled[backend] = true
+		s.mu.Unlock()
+		return
+	}
+	if answer.status != 0 {
+		w.WriteHeader(answer.status)
+	}
+	fmt.Fprint(w, answer.body)
+}
+
+func (s *textServer) searchOrder() []Backend {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Backend(nil), s.searched...)
+}
+
+func (s *textServer) startedAt(backend Backend) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started[backend]
+}
+
+/**
+* Whether the request to backend was cancelled, waiting up to a second for
+* the server to notice.
+**/
+func (s *textServer) wasCancelled(backend Backend) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		s.mu.Lock()
+		cancelled := s.cancelled[backend]
+		s.mu.Unlock()
+		if cancelled {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSearchTextAuto(t *testing.T) {
+	ratelimited := backendAnswer{status: http.StatusTooManyRequests}
+	tests := []struct {
+		name         string
+		backends     []Backend
+		answers      map[Backend]backendAnswer
+		wantSearched []Backend
+		wantBackend  Backend
+		wantTitle    string
+	}{
+		{
+			name:         "api succeeds",
+			answers:      map[Backend]backendAnswer{BackendAPI: {body: apiBody("api")}},
+			wantSearched: []Backend{BackendAPI},
+			wantBackend:  BackendAPI,
+			wantTitle:    "api",
+		},
+		{
+			name: "api rate limited",
+			answers: map[Backend]backendAnswer{
+				BackendAPI:  ratelimited,
+				BackendHTML: {body: htmlBody("html")},
+			},
+			wantSearched: []Backend{BackendAPI, BackendHTML},
+			wantBackend:  BackendHTML,
+			wantTitle:    "html",
+		},
+		{
+			name: "api page not parsable",
+			answers: map[Backend]backendAnswer{
+				BackendAPI:  {body: "DDG.deep.anomalyDetectionBlock"},
+				BackendHTML: {body: htmlBody("html")},
+			},
+			wantSearched: []Backend{BackendAPI, BackendHTML},
+			wantBackend:  BackendHTML,
+			wantTitle:    "html",
+		},
+		{
+			name: "api and html find nothing",
+			answers: map[Backend]backendAnswer{
+				BackendAPI:  {body: apiBody()},
+				BackendHTML: {body: htmlBody()},
+				BackendLite: {body: liteBody("lite")},
+			},
+			wantSearched: []Backend{BackendAPI, BackendHTML, BackendLite},
+			wantBackend:  BackendLite,
+			wantTitle:    "lite",
+		},
+		{
+			name:     "custom order",
+			backends: []Backend{BackendLite, BackendAPI},
+			answers: map[Backend]backendAnswer{
+				BackendAPI:  {body: apiBody("api")},
+				BackendHTML: {body: htmlBody("html")},
+				BackendLite: ratelimited,
+			},
+			wantSearched: []Backend{BackendLite, BackendAPI},
+			wantBackend:  BackendAPI,
+			wantTitle:    "api",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTextServer(tt.answers)
+			a := newTestClient(t, srv)
+			resp, err := a.SearchText(context.Background(), TextRequest{Keywords: "test", Backend: BackendAuto, Backends: tt.backends})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.searchOrder(); !reflect.DeepEqual(got, tt.wantSearched) {
+				t.Errorf("searched %v, want %v", got, tt.wantSearched)
+			}
+			if resp.Backend != tt.wantBackend {
+				t.Errorf("Backend = %s, want %s", resp.Backend, tt.wantBackend)
+			}
+			if len(resp.Results) != 1 || resp.Results[0].Title != tt.wantTitle {
+				t.Errorf("results = %+v, want the result of %s", resp.Results, tt.wantBackend)
+			}
+		})
+	}
+}
+
+func TestSearchTextAutoAllFail(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {status: http.StatusTooManyRequests},
+		BackendHTML: {status: http.StatusNotFound},
+		BackendLite: {body: liteBody()},
+	})
+	a := newTestClient(t, srv)
+	resp, err := a.SearchText(context.Background(), TextRequest{Keywords: "test", Backend: BackendAuto})
+	if resp == nil || len(resp.Results) != 0 {
+		t.Errorf("response = %+v, want one without results", resp)
+	}
+	var ratelimitErr *RatelimitError
+	var statusErr *StatusError
+	if !errors.As(err, &ratelimitErr) || !errors.As(err, &statusErr) {
+		t.Errorf("got error %v, want the errors of api and html joined", err)
+	}
+	for _, want := range []string{"backend api", "backend html", "backend lite: no results"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("got error %v, want it to name %q", err, want)
+		}
+	}
+}
+
+func TestTextIterAuto(t *testing.T) {
+	srv := newTextServer(map[Backend]backendAnswer{
+		BackendAPI:  {status: http.StatusTooManyRequests},
+		BackendHTML: {body: htmlBody()},
+		BackendLite: {body: liteBody("lite")},
+	})
+	a := newTestClient(t, srv)
+	it := a.TextIter(context.Background(), TextRequest{Keywords: "test", Backend: BackendAuto})
+	defer it.Close()
+	var titles []string
+	for it.Next() {
+		titles = append(titles, it.Result().Title)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(titles, []string{"lite"}) {
+		t.Errorf("titles = %v, want the result of lite", titles)
+	}
+}
+
+var textBodies = map[Backend]func(titles ...string) string{
+	BackendAPI:  apiBody,
+	BackendHTML: htmlBody,
+	BackendLite: liteBody,
+}
+
+func TestSearchTextMaxResults(t *testing.T) {
+	const pageSize = 50
+	tests := []struct {
+		name        string
+		maxResults  int
+		wantResults int
+		wantOffsets []int
+		wantErr     error
+	}{
+		{name: "first page", maxResults: 0, wantResults: pageSize, wantOffsets: []int{0}},
+		{name: "within the first page", maxResults: 10, wantResults: 10, wantOffsets: []int{0}},
+		{name: "two pages", maxResults: 60, wantResults: 60, wantOffsets: []int{0, 23}},
+		{name: "above the cap", maxResults: MaxTextResults + 1, wantErr: ErrMaxResultsExceeded},
+	}
+	for _, backend := range []Backend{BackendAPI, BackendHTML, BackendLite} {
+		for _, tt := range tests {
+			t.Run(string(backend)+"/"+tt.name, func(t *testing.T) {
+				var mu sync.Mutex
+				var offsets []int
+				a := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if _, ok := textBackendPaths[r.URL.Path]; !ok {
+						fmt.Fprint(w, `vqd="4-123"`)
+						return
+					}
+					offset, _ := strconv.Atoi(r.FormValue("s"))
+					mu.Lock()
+					offsets = append(offsets, offset)
+					mu.Unlock()
+					titles := make([]string, pageSize)
+					for i := range titles {
+						titles[i] = "r" + strconv.Itoa(offset+i)
+					}
+					fmt.Fprint(w, textBodies[backend](titles...))
+				}))
+				results, err := a.TextContext(context.Background(), TextRequest{Keywords: "test", Backend: backend, MaxResults: tt.maxResults})
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("got error %v, want %v", err, tt.wantErr)
+					}
+					if len(offsets) != 0 {
+						t.Errorf("requested pages %v of an invalid search", offsets)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(results) != tt.wantResults {
+					t.Fatalf("got %d results, want %d", len(results), tt.wantResults)
+				}
+				for i, result := range results {
+					if want := "r" + strconv.Itoa(i); result.Title != want {
+						t.Fatalf("result %d is %q, want %q in rank order without duplicates", i, result.Title, want)
+					}
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				sort.Ints(offsets)
+				if !reflect.DeepEqual(offsets, tt.wantOffsets) {
+					t.Errorf("requested offsets %v, want %v", offsets, tt.wantOffsets)
+				}
+			})
+		}
+	}
+}
+
+func TestParseTextLite(t *testing.T) {
+	const (
+		result = `<tr><td>1.&nbsp;</td><td><a rel="nofollow" href="%s" class="result-link">%s</a></td></tr>
+			<tr><td></td><td class="result-snippet">%s</td></tr>
+			<tr><td></td><td><span class="link-text">%s</span></td></tr>
+			<tr><td>&nbsp;</td></tr>`
+		header = `<table><tr><td><a href="https://duckduckgo.com/">DuckDuckGo</a></td></tr></table>`
+	)
+	tests := []struct {
+		name string
+		body string
+		want []TextResult
+	}{
+		{
+			name: "results",
+			body: header + "<table>" +
+				fmt.Sprintf(result, "https://go.dev/", "The Go <b>Programming</b> Language", "Go is an <b>open source</b> language.", "go.dev") +
+				fmt.Sprintf(result, "https://pkg.go.dev/", "Go Packages", "Search packages.", "pkg.go.dev") +
+				"</table>",
+			want: []TextResult{
+				{Title: "The Go Programming Language", Href: "https://go.dev/", Body: "Go is an open source language.", DisplayURL: "go.dev", Favicon: faviconURL("", "https://go.dev/")},
+				{Title: "Go Packages", Href: "https://pkg.go.dev/", Body: "Search packages.", DisplayURL: "pkg.go.dev", Favicon: faviconURL("", "https://pkg.go.dev/")},
+			},
+		},
+		{
+			name: "ad skipped",
+			body: header + "<table>" +
+				fmt.Sprintf(result, "https://duckduckgo.com/y.js?ad_domain=example.com", "Ad", "Buy now.", "example.com") +
+				fmt.Sprintf(result, "https://go.dev/", "Go", "Go is fun.", "go.dev") +
+				"</table>",
+			want: []TextResult{
+				{Title: "Go", Href: "https://go.dev/", Body: "Go is fun.", DisplayURL: "go.dev", Favicon: faviconURL("", "https://go.dev/")},
+			},
+		},
+		{
+			name: "last result without display url",
+			body: header + `<table><tr><td><a href="https://go.dev/">Go</a></td></tr><tr><td class="result-snippet">Go is fun.</td></tr></table>`,
+			want: []TextResult{
+				{Title: "Go", Href: "https://go.dev/", Body: "Go is fun.", Favicon: faviconURL("", "https://go.dev/")},
+			},
+		},
+		{
+			name: "no more results",
+			body: header + `<table><tr><td>No more results.</td></tr></table>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTextLite([]byte(tt.body), "go")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTextLite() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTextHTML(t *testing.T) {
+	body := `<div class="result"><h2 class="result__title"><a class="result__a" href="https://go.dev/">The Go <b>Programming</b> Language</a></h2>
+		<div class="result__extras"><div class="result__extras__url"><a class="result__url" href="https://go.dev/"> go.dev </a></div></div>
+		<a class="result__snippet" href="https://go.dev/">Go is an <b>open source</b> language.</a></div>
+		<div class="result"><h2><a href="https://duckduckgo.com/y.js?ad_domain=example.com">Ad</a></h2><a href="https://duckduckgo.com/y.js?ad_domain=example.com">Buy now.</a></div>`
+	got, err := parseTextHTML([]byte(body), "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TextResult{{
+		Title:      "The Go Programming Language",
+		Href:       "https://go.dev/",
+		Body:       "Go is an open source language.",
+		DisplayURL: "go.dev",
+		Favicon:    faviconURL("", "https://go.dev/"),
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTextHTML() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTextAPI(t *testing.T) {
+	published := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	body := `DDG.pageLayout.load('d',[
+		{"t":"The Go <b>Programming</b> Language","u":"https://go.dev/","a":"Go is an <b>open source</b> language.",
+			"d":"go.dev","i":"go.dev","e":"2024-03-01T10:30:00",
+			"l":[{"targetUrl":"https://go.dev/doc/","text":"Documentation","snippet":"Learn <b>Go</b>."},{"text":"No link"}]},
+		{"t":"Go Packages","u":"https://pkg.go.dev/","a":"Search packages.","e":"not a date"},
+		{"t":"Google","u":"http://www.google.com/search?q=go","a":"More results"},
+		{"t":"Empty","u":"https://empty.example/","a":""}
+	]);DDG.duckbar.load('images');`
+	got, err := parseTextAPI([]byte(body), "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TextResult{
+		{
+			Title:      "The Go Programming Language",
+			Href:       "https://go.dev/",
+			Body:       "Go is an open source language.",
+			DisplayURL: "go.dev",
+			Favicon:    "https://external-content.duckduckgo.com/ip3/go.dev.ico",
+			Published:  &published,
+			Sitelinks:  []Sitelink{{Title: "Documentation", URL: "https://go.dev/doc/", Snippet: "Learn Go."}},
+		},
+		{
+			Title:   "Go Packages",
+			Href:    "https://pkg.go.dev/",
+			Body:    "Search packages.",
+			Favicon: "https://external-content.duckduckgo.com/ip3/pkg.go.dev.ico",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTextAPI() =\n%+v\nwant\n%+v", got, want)
+	}
+}
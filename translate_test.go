@@ -0,0 +1,47 @@
+package duckduckgo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestTranslatePartialFailure(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translation.js" {
+			fmt.Fprint(w, `vqd="4-123"`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "kaputt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"translated":"%s!"}`, body)
+	})
+	keywords := []string{"hallo", "kaputt", "welt"}
+
+	a := newTestClient(t, handler)
+	result, err := a.Translate(keywords, "de", "en")
+	if len(result) != 2 || result["hallo"] != "hallo!" || result["welt"] != "welt!" {
+		t.Errorf("result = %v, want the two working keywords", result)
+	}
+	var partialErr *PartialTranslationError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("got error %v (%T), want *PartialTranslationError", err, err)
+	}
+	if partialErr.Keywords != 3 || len(partialErr.Failed) != 1 || partialErr.Failed[0].Keyword != "kaputt" {
+		t.Errorf("error = %v, want only kaputt failed", partialErr)
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Errorf("errors.As(%v) found no *StatusError", err)
+	}
+
+	strict := newTestClient(t, handler, WithStrict(true))
+	if result, err := strict.Translate(keywords, "de", "en"); result != nil || !errors.As(err, &partialErr) {
+		t.Errorf("strict Translate() = %v, %v; want no result and the error", result, err)
+	}
+}
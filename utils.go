@@ -1,13 +1,16 @@
 package duckduckgo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"html"
 	"math"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -54,9 +57,13 @@ func extractVQD(htmlBytes []byte, keywords string) (string, error) {
  * text(backend="api") -> extract json from html.
 **/
 func textExtractJSON(htmlBytes []byte, keywords string) ([]map[string]any, error) {
-	start := strings.Index(string(htmlBytes), "DDG.pageLayout.load('d',") + 24
+	start := strings.Index(string(htmlBytes), "DDG.pageLayout.load('d',")
+	if start < 0 {
+		return nil, fmt.Errorf("textExtractJSON() keywords=%s return None", keywords)
+	}
+	start += 24
 	end := strings.Index(string(htmlBytes[start:]), ");DDG.duckbar.load(")
-	if start < 0 || end < 0 {
+	if end < 0 {
 		return nil, fmt.Errorf("textExtractJSON() keywords=%s return None", keywords)
 	}
 	data := htmlBytes[start : start+end]
@@ -116,3 +123,60 @@ func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 	return R * c
 }
+
+/**
+* Parse a clip length like "4:13" or "1:02:03" into a time.Duration.
+**/
+func parseClipDuration(clip string) time.Duration {
+	var d time.Duration
+	for _, part := range strings.Split(clip, ":") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second
+}
+
+/**
+* Parse a timestamp in any of the layouts DuckDuckGo uses. Zero time if none match.
+**/
+func parseTimestamp(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.9999999", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+/**
+* A JSON number that may also be quoted or null; 0 if it is neither.
+**/
+func rawNumber(raw json.RawMessage) float64 {
+	n, err := strconv.ParseFloat(strings.Trim(string(raw), `"`), 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+/**
+* A JSON string as is; any other JSON value as its compact JSON, "" for null.
+**/
+func rawText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return ""
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}